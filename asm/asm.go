@@ -0,0 +1,439 @@
+package asm
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const defaultOrigin = 0x200
+
+// Error is an error on source, at Line and Column (both starting on 1)
+type Error struct {
+	File   string
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *Error) Error() string {
+	if e.File == "" {
+		return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Msg)
+	}
+
+	return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Column, e.Msg)
+}
+
+// Program is the result of assembly
+type Program struct {
+	// Rom is the binary of program, loaded on the origin address
+	Rom []byte
+
+	// Symbols are the addresses of labels
+	Symbols map[string]uint16
+}
+
+// Assembler turns the source on Cowgod's syntax into a ROM:
+//
+//	; comment
+//	SPEED EQU 2            ; constant, the value may be an expression
+//	start:                 ; label, that may be followed by an instruction
+//		LD V0, SPEED * 3
+//		LD I, sprite
+//		DRW V0, V1, sprite_end - sprite
+//		JP start
+//	sprite:
+//		DB 0xF0, 0b10010000, "text"
+//		DW 0x1234
+//	sprite_end:
+//	include "other.asm"    ; path relative to the file that includes it
+//
+// Mnemonics, registers and keywords are case insensitive, symbols are case sensitive
+// The expressions have the operators of Go on integers: | ^ & << >> + - * / % and unary - + ~
+type Assembler struct {
+	fs     fs.FS
+	origin uint16
+}
+
+type ConfigAssembler struct {
+	// FS is where the included files are read, when nil include is not allowed
+	FS fs.FS
+
+	// Address where ROM is loaded, when zero it is 0x200
+	Origin uint16
+}
+
+// NewAssembler is a function that receive a config as param and return a pointer to Assembler
+func NewAssembler(config *ConfigAssembler) *Assembler {
+	origin := config.Origin
+	if origin == 0 {
+		origin = defaultOrigin
+	}
+
+	return &Assembler{fs: config.FS, origin: origin}
+}
+
+// position is a position on source, col 0 means the whole line
+type position struct {
+	file string
+	line int
+	col  int
+}
+
+func (p position) at(col int) position {
+	p.col = col
+	return p
+}
+
+// statement is a line of source after the label
+type statement struct {
+	pos      position
+	label    string
+	mnemonic string
+	operands [][]token
+	form     instruction
+	size     int
+	addr     uint16
+
+	// state of constants (EQU)
+	value      int64
+	evaluating bool
+	evaluated  bool
+}
+
+// assembly holds the state of one call to Assemble
+type assembly struct {
+	*Assembler
+	statements []*statement
+	labels     map[string]uint16
+	constants  map[string]*statement
+	defined    map[string]bool
+	including  []string
+}
+
+// Assemble returns the program of src, name is the file of src used on errors and includes
+// The error returned is an *Error when it is on source
+func (a *Assembler) Assemble(name string, src io.Reader) (*Program, error) {
+	as := &assembly{
+		Assembler: a,
+		labels:    map[string]uint16{},
+		constants: map[string]*statement{},
+		defined:   map[string]bool{},
+	}
+
+	if err := as.parse(name, src); err != nil {
+		return nil, err
+	}
+
+	if err := as.layout(); err != nil {
+		return nil, err
+	}
+
+	rom := &bytes.Buffer{}
+	for _, s := range as.statements {
+		data, err := as.emit(s)
+		if err != nil {
+			return nil, err
+		}
+		rom.Write(data)
+	}
+
+	return &Program{Rom: rom.Bytes(), Symbols: as.labels}, nil
+}
+
+// parse reads the statements of src, including the files
+func (as *assembly) parse(name string, src io.Reader) error {
+	as.including = append(as.including, name)
+	defer func() { as.including = as.including[:len(as.including)-1] }()
+
+	scanner := bufio.NewScanner(src)
+	for line := 1; scanner.Scan(); line++ {
+		pos := position{file: name, line: line}
+
+		tokens, col, err := lex(scanner.Text())
+		if err != nil {
+			return pos.at(col).errorf("%s", err.Error())
+		}
+
+		if err := as.parseLine(tokens, pos); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
+
+func (as *assembly) parseLine(tokens []token, pos position) error {
+	if len(tokens) >= 2 && tokens[0].kind == tokenIdent && tokens[1].text == ":" {
+		if err := as.checkSymbol(tokens[0], pos); err != nil {
+			return err
+		}
+		as.statements = append(as.statements, &statement{pos: pos.at(tokens[0].col), label: tokens[0].text})
+		tokens = tokens[2:]
+	}
+
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	if tokens[0].kind != tokenIdent {
+		return pos.at(tokens[0].col).errorf("instruction expected")
+	}
+
+	// NAME EQU expression
+	if len(tokens) >= 2 && strings.ToUpper(tokens[1].text) == "EQU" {
+		if err := as.checkSymbol(tokens[0], pos); err != nil {
+			return err
+		}
+		as.constants[tokens[0].text] = &statement{pos: pos.at(tokens[1].col), operands: [][]token{tokens[2:]}}
+		return nil
+	}
+
+	s := &statement{pos: pos.at(tokens[0].col), mnemonic: strings.ToUpper(tokens[0].text)}
+	operands, err := splitOperands(tokens[1:], pos)
+	if err != nil {
+		return err
+	}
+	s.operands = operands
+
+	if s.mnemonic == "INCLUDE" {
+		return as.include(s)
+	}
+
+	as.statements = append(as.statements, s)
+	return nil
+}
+
+// include parses the file named by the operand of s
+func (as *assembly) include(s *statement) error {
+	if len(s.operands) != 1 || len(s.operands[0]) != 1 || s.operands[0][0].kind != tokenString {
+		return s.pos.errorf("include expects a file name between quotes")
+	}
+
+	name, err := strconv.Unquote(s.operands[0][0].text)
+	if err != nil {
+		return s.pos.at(s.operands[0][0].col).errorf("invalid string %s", s.operands[0][0].text)
+	}
+	name = path.Join(path.Dir(s.pos.file), name)
+
+	if as.fs == nil {
+		return s.pos.errorf("include is not allowed")
+	}
+	if contains(as.including, name) {
+		return s.pos.errorf("include cycle on %s", name)
+	}
+
+	f, err := as.fs.Open(name)
+	if err != nil {
+		return s.pos.errorf("%s", err.Error())
+	}
+	defer f.Close()
+
+	return as.parse(name, f)
+}
+
+// layout finds the form of instructions and the address of statements
+func (as *assembly) layout() error {
+	addr := int(as.origin)
+
+	for _, s := range as.statements {
+		s.addr = uint16(addr)
+
+		switch s.mnemonic {
+		case "":
+			as.labels[s.label] = s.addr
+		case "DB":
+			for _, op := range s.operands {
+				if len(op) == 1 && op[0].kind == tokenString {
+					text, err := strconv.Unquote(op[0].text)
+					if err != nil {
+						return s.pos.at(op[0].col).errorf("invalid string %s", op[0].text)
+					}
+					s.size += len(text)
+					continue
+				}
+				s.size++
+			}
+		case "DW":
+			s.size = 2 * len(s.operands)
+		default:
+			if err := as.findForm(s); err != nil {
+				return err
+			}
+			s.size = s.form.size()
+		}
+
+		addr += s.size
+		if addr > 0x10000 {
+			return s.pos.errorf("program beyond the address 0xFFFF")
+		}
+	}
+
+	return nil
+}
+
+// findForm finds the form of instruction that matches the operands of s
+func (as *assembly) findForm(s *statement) error {
+	operands := make([]operand, len(s.operands))
+	for idx, tokens := range s.operands {
+		operands[idx] = classify(tokens)
+	}
+
+	known := false
+	for _, form := range instructions {
+		if form.mnemonic != s.mnemonic {
+			continue
+		}
+		known = true
+
+		if form.match(operands) {
+			s.form = form
+			return nil
+		}
+	}
+
+	if !known {
+		return s.pos.errorf("unknown instruction %s", s.mnemonic)
+	}
+
+	return s.pos.errorf("invalid operands of %s", s.mnemonic)
+}
+
+// emit returns the bytes of statement
+func (as *assembly) emit(s *statement) ([]byte, error) {
+	switch s.mnemonic {
+	case "":
+		return nil, nil
+	case "DB":
+		data := []byte{}
+		for _, op := range s.operands {
+			if len(op) == 1 && op[0].kind == tokenString {
+				text, _ := strconv.Unquote(op[0].text)
+				data = append(data, text...)
+				continue
+			}
+
+			value, err := as.evalRange(op, s.pos, -0x80, 0xFF)
+			if err != nil {
+				return nil, err
+			}
+			data = append(data, byte(value))
+		}
+		return data, nil
+	case "DW":
+		data := []byte{}
+		for _, op := range s.operands {
+			value, err := as.evalRange(op, s.pos, -0x8000, 0xFFFF)
+			if err != nil {
+				return nil, err
+			}
+			data = append(data, byte(value>>8), byte(value))
+		}
+		return data, nil
+	}
+
+	operands := make([]operand, len(s.operands))
+	for idx, tokens := range s.operands {
+		operands[idx] = classify(tokens)
+	}
+
+	return as.encode(s.form, operands, s.pos)
+}
+
+// evalRange evaluates tokens, that must be between min and max
+func (as *assembly) evalRange(tokens []token, pos position, min, max int64) (int64, error) {
+	if len(tokens) > 0 {
+		pos = pos.at(tokens[0].col)
+	}
+
+	value, err := as.eval(tokens, pos)
+	if err != nil {
+		return 0, err
+	}
+
+	if value < min || value > max {
+		return 0, pos.errorf("value %d out of range %d..%d", value, min, max)
+	}
+
+	return value, nil
+}
+
+// symbol returns the value of a label or a constant
+func (as *assembly) symbol(name string, pos position) (int64, error) {
+	if addr, ok := as.labels[name]; ok {
+		return int64(addr), nil
+	}
+
+	constant, ok := as.constants[name]
+	if !ok {
+		return 0, pos.errorf("undefined symbol %s", name)
+	}
+
+	if !constant.evaluated {
+		if constant.evaluating {
+			return 0, pos.errorf("constant %s refers to itself", name)
+		}
+
+		constant.evaluating = true
+		value, err := as.eval(constant.operands[0], constant.pos)
+		constant.evaluating = false
+		if err != nil {
+			return 0, err
+		}
+
+		constant.value, constant.evaluated = value, true
+	}
+
+	return constant.value, nil
+}
+
+// checkSymbol returns an error when the name of symbol is reserved or already defined, else defines it
+func (as *assembly) checkSymbol(tok token, pos position) error {
+	if isReserved(tok.text) {
+		return pos.at(tok.col).errorf("%s is reserved", tok.text)
+	}
+
+	if as.defined[tok.text] {
+		return pos.at(tok.col).errorf("%s already defined", tok.text)
+	}
+
+	as.defined[tok.text] = true
+	return nil
+}
+
+// splitOperands splits tokens on commas out of parentheses
+func splitOperands(tokens []token, pos position) ([][]token, error) {
+	operands := [][]token{}
+	if len(tokens) == 0 {
+		return operands, nil
+	}
+
+	depth, start := 0, 0
+	for idx, tok := range tokens {
+		switch tok.text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+		case ",":
+			if depth == 0 {
+				if idx == start {
+					return nil, pos.at(tok.col).errorf("operand expected")
+				}
+				operands = append(operands, tokens[start:idx])
+				start = idx + 1
+			}
+		}
+	}
+
+	if start == len(tokens) {
+		return nil, pos.at(tokens[len(tokens)-1].col).errorf("operand expected")
+	}
+
+	return append(operands, tokens[start:]), nil
+}
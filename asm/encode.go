@@ -0,0 +1,244 @@
+package asm
+
+import (
+	"strings"
+)
+
+// Kinds of operand, the keywords are the operand in upper case
+const (
+	operandRegister   = "V"
+	operandLong       = "LONG"
+	operandExpression = "N"
+)
+
+// Minimum and maximum values of the operands that are expressions, a byte may be negative
+var operandLimits = map[string][2]int64{
+	"a": {0, 0xFFF},
+	"b": {-0x80, 0xFF},
+	"n": {0, 0xF},
+	"p": {0, 0xF},
+	"l": {0, 0xFFFF},
+}
+
+// Operands that are written as keywords
+var keywords = []string{"I", "[I]", "DT", "ST", "K", "F", "HF", "B", "R"}
+
+type operand struct {
+	kind     string
+	register byte
+	tokens   []token
+	col      int
+}
+
+// instruction is a form of a mnemonic, its operands are written as:
+//
+//	x, y  a register placed on X or Y of opcode
+//	s     a register placed on both X and Y (SHR and SHL with one register)
+//	V0    the register V0
+//	a     an address of 12 bits
+//	b     a byte
+//	n     a nibble placed on N
+//	p     a nibble placed on X (PLANE)
+//	l     LONG and an address of 16 bits, written on a second word
+//	      and the keywords I, [I], DT, ST, K, F, HF, B and R
+type instruction struct {
+	mnemonic string
+	operands string
+	opcode   uint16
+}
+
+var instructions = []instruction{
+	{"CLS", "", 0x00E0},
+	{"RET", "", 0x00EE},
+	{"SCD", "n", 0x00C0},
+	{"SCU", "n", 0x00D0},
+	{"SCR", "", 0x00FB},
+	{"SCL", "", 0x00FC},
+	{"EXIT", "", 0x00FD},
+	{"LOW", "", 0x00FE},
+	{"HIGH", "", 0x00FF},
+	{"SYS", "a", 0x0000},
+	{"JP", "a", 0x1000},
+	{"JP", "V0 a", 0xB000},
+	{"CALL", "a", 0x2000},
+	{"SE", "x b", 0x3000},
+	{"SE", "x y", 0x5000},
+	{"SNE", "x b", 0x4000},
+	{"SNE", "x y", 0x9000},
+	{"SAVE", "x y", 0x5002},
+	{"LOAD", "x y", 0x5003},
+	{"LD", "x b", 0x6000},
+	{"LD", "x y", 0x8000},
+	{"LD", "I a", 0xA000},
+	{"LD", "I l", 0xF000},
+	{"LD", "x DT", 0xF007},
+	{"LD", "x K", 0xF00A},
+	{"LD", "DT x", 0xF015},
+	{"LD", "ST x", 0xF018},
+	{"LD", "F x", 0xF029},
+	{"LD", "HF x", 0xF030},
+	{"LD", "B x", 0xF033},
+	{"LD", "[I] x", 0xF055},
+	{"LD", "x [I]", 0xF065},
+	{"LD", "R x", 0xF075},
+	{"LD", "x R", 0xF085},
+	{"ADD", "x b", 0x7000},
+	{"ADD", "x y", 0x8004},
+	{"ADD", "I x", 0xF01E},
+	{"OR", "x y", 0x8001},
+	{"AND", "x y", 0x8002},
+	{"XOR", "x y", 0x8003},
+	{"SUB", "x y", 0x8005},
+	{"SHR", "x y", 0x8006},
+	{"SHR", "s", 0x8006},
+	{"SUBN", "x y", 0x8007},
+	{"SHL", "x y", 0x800E},
+	{"SHL", "s", 0x800E},
+	{"RND", "x b", 0xC000},
+	{"DRW", "x y n", 0xD000},
+	{"SKP", "x", 0xE09E},
+	{"SKNP", "x", 0xE0A1},
+	{"PLANE", "p", 0xF001},
+	{"AUDIO", "", 0xF002},
+	{"PITCH", "x", 0xF03A},
+}
+
+// classify returns the kind of operand written by tokens
+func classify(tokens []token) operand {
+	op := operand{kind: operandExpression, tokens: tokens}
+	if len(tokens) == 0 {
+		return op
+	}
+	op.col = tokens[0].col
+
+	text := ""
+	for _, tok := range tokens {
+		text += strings.ToUpper(tok.text)
+	}
+
+	switch {
+	case len(tokens) == 1 && isRegister(text):
+		op.kind = operandRegister
+		op.register = hexDigit(text[1])
+	case contains(keywords, text):
+		op.kind = text
+	case tokens[0].kind == tokenIdent && strings.ToUpper(tokens[0].text) == operandLong && len(tokens) > 1:
+		op.kind = operandLong
+		op.tokens = tokens[1:]
+	}
+
+	return op
+}
+
+// match returns true when operands are of the kinds of form
+func (instr instruction) match(operands []operand) bool {
+	kinds := strings.Fields(instr.operands)
+	if len(kinds) != len(operands) {
+		return false
+	}
+
+	for idx, kind := range kinds {
+		op := operands[idx]
+		switch kind {
+		case "x", "y", "s":
+			if op.kind != operandRegister {
+				return false
+			}
+		case "V0":
+			if op.kind != operandRegister || op.register != 0 {
+				return false
+			}
+		case "a", "b", "n", "p":
+			if op.kind != operandExpression {
+				return false
+			}
+		case "l":
+			if op.kind != operandLong {
+				return false
+			}
+		default:
+			if op.kind != kind {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// size returns the bytes of instruction
+func (instr instruction) size() int {
+	if strings.Contains(instr.operands, "l") {
+		return 4
+	}
+
+	return 2
+}
+
+// encode returns the bytes of instruction with operands
+func (as *assembly) encode(instr instruction, operands []operand, pos position) ([]byte, error) {
+	opcode := instr.opcode
+	long := -1
+
+	for idx, kind := range strings.Fields(instr.operands) {
+		op := operands[idx]
+		reg := uint16(op.register)
+
+		switch kind {
+		case "x":
+			opcode |= reg << 8
+		case "y":
+			opcode |= reg << 4
+		case "s":
+			opcode |= reg<<8 | reg<<4
+		case "a", "b", "n", "p", "l":
+			limits := operandLimits[kind]
+
+			value, err := as.eval(op.tokens, pos.at(op.col))
+			if err != nil {
+				return nil, err
+			}
+			if value < limits[0] || value > limits[1] {
+				return nil, pos.at(op.col).errorf("value %d out of range %d..%d", value, limits[0], limits[1])
+			}
+
+			switch kind {
+			case "a":
+				opcode |= uint16(value)
+			case "b":
+				opcode |= uint16(value) & 0xFF
+			case "n":
+				opcode |= uint16(value)
+			case "p":
+				opcode |= uint16(value) << 8
+			case "l":
+				long = int(value)
+			}
+		}
+	}
+
+	if long >= 0 {
+		return []byte{byte(opcode >> 8), byte(opcode), byte(long >> 8), byte(long)}, nil
+	}
+
+	return []byte{byte(opcode >> 8), byte(opcode)}, nil
+}
+
+// isRegister returns true for the names V0..VF, in upper case
+func isRegister(name string) bool {
+	return len(name) == 2 && name[0] == 'V' && strings.IndexByte("0123456789ABCDEF", name[1]) >= 0
+}
+
+func hexDigit(c byte) byte {
+	if c >= 'A' {
+		return c - 'A' + 0xA
+	}
+
+	return c - '0'
+}
+
+// isReserved returns true for the names that can not be symbols
+func isReserved(name string) bool {
+	name = strings.ToUpper(name)
+	return isRegister(name) || contains(keywords, name) || name == operandLong
+}
@@ -0,0 +1,162 @@
+package asm
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Binary operators by precedence, from the lowest
+var binaryOperators = [][]string{
+	{"|"},
+	{"^"},
+	{"&"},
+	{"<<", ">>"},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+// expression evaluates a list of tokens, resolving the symbols by value
+type expression struct {
+	tokens []token
+	idx    int
+	value  func(name string, col int) (int64, error)
+	pos    position
+}
+
+// eval returns the value of tokens, that must be a whole expression
+func (as *assembly) eval(tokens []token, pos position) (int64, error) {
+	if len(tokens) == 0 {
+		return 0, pos.errorf("expression expected")
+	}
+
+	e := &expression{tokens: tokens, pos: pos, value: func(name string, col int) (int64, error) {
+		return as.symbol(name, pos.at(col))
+	}}
+
+	result, err := e.binary(0)
+	if err != nil {
+		return 0, err
+	}
+
+	if e.idx < len(e.tokens) {
+		return 0, pos.at(e.tokens[e.idx].col).errorf("unexpected %s", e.tokens[e.idx].text)
+	}
+
+	return result, nil
+}
+
+func (e *expression) binary(level int) (int64, error) {
+	if level == len(binaryOperators) {
+		return e.unary()
+	}
+
+	left, err := e.binary(level + 1)
+	if err != nil {
+		return 0, err
+	}
+
+	for e.idx < len(e.tokens) && e.tokens[e.idx].kind == tokenPunct && contains(binaryOperators[level], e.tokens[e.idx].text) {
+		op := e.tokens[e.idx]
+		e.idx++
+
+		right, err := e.binary(level + 1)
+		if err != nil {
+			return 0, err
+		}
+
+		switch op.text {
+		case "|":
+			left |= right
+		case "^":
+			left ^= right
+		case "&":
+			left &= right
+		case "<<":
+			left <<= uint(right)
+		case ">>":
+			left >>= uint(right)
+		case "+":
+			left += right
+		case "-":
+			left -= right
+		case "*":
+			left *= right
+		case "/", "%":
+			if right == 0 {
+				return 0, e.pos.at(op.col).errorf("division by zero")
+			}
+			if op.text == "/" {
+				left /= right
+			} else {
+				left %= right
+			}
+		}
+	}
+
+	return left, nil
+}
+
+func (e *expression) unary() (int64, error) {
+	if e.idx >= len(e.tokens) {
+		return 0, e.end().errorf("expression expected")
+	}
+
+	tok := e.tokens[e.idx]
+	e.idx++
+
+	switch {
+	case tok.kind == tokenPunct && tok.text == "-":
+		value, err := e.unary()
+		return -value, err
+	case tok.kind == tokenPunct && tok.text == "+":
+		return e.unary()
+	case tok.kind == tokenPunct && tok.text == "~":
+		value, err := e.unary()
+		return ^value, err
+	case tok.kind == tokenPunct && tok.text == "(":
+		value, err := e.binary(0)
+		if err != nil {
+			return 0, err
+		}
+		if e.idx >= len(e.tokens) || e.tokens[e.idx].text != ")" {
+			return 0, e.end().errorf("missing )")
+		}
+		e.idx++
+		return value, nil
+	case tok.kind == tokenNumber:
+		value, err := strconv.ParseInt(tok.text, 0, 64)
+		if err != nil {
+			return 0, e.pos.at(tok.col).errorf("invalid number %s", tok.text)
+		}
+		return value, nil
+	case tok.kind == tokenIdent:
+		return e.value(tok.text, tok.col)
+	}
+
+	return 0, e.pos.at(tok.col).errorf("unexpected %s", tok.text)
+}
+
+// end returns the position of current token, or after the last token
+func (e *expression) end() position {
+	if e.idx < len(e.tokens) {
+		return e.pos.at(e.tokens[e.idx].col)
+	}
+
+	last := e.tokens[len(e.tokens)-1]
+	return e.pos.at(last.col + len(last.text))
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// errorf returns an Error on position
+func (p position) errorf(format string, args ...interface{}) *Error {
+	return &Error{File: p.file, Line: p.line, Column: p.col, Msg: fmt.Sprintf(format, args...)}
+}
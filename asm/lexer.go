@@ -0,0 +1,91 @@
+package asm
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokenNumber tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenPunct
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	col  int
+}
+
+// Punctuations of two chars, other punctuations are of one char
+var punct2 = []string{"<<", ">>"}
+
+const punct1 = ",:()[]+-*/%&|^~"
+
+// lex splits line into tokens, stopping on comment ";"
+// The columns start on 1, the error returned is the column of invalid char
+func lex(line string) ([]token, int, error) {
+	tokens := []token{}
+
+	for i := 0; i < len(line); {
+		c := line[i]
+		start := i
+
+		switch {
+		case c == ';':
+			return tokens, 0, nil
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+			continue
+		case isIdentStart(c):
+			for i < len(line) && isIdentChar(line[i]) {
+				i++
+			}
+			tokens = append(tokens, token{tokenIdent, line[start:i], start + 1})
+		case c >= '0' && c <= '9':
+			for i < len(line) && isIdentChar(line[i]) {
+				i++
+			}
+			tokens = append(tokens, token{tokenNumber, line[start:i], start + 1})
+		case c == '"':
+			for i++; i < len(line) && line[i] != '"'; i++ {
+				if line[i] == '\\' {
+					i++
+				}
+			}
+			if i >= len(line) {
+				return nil, start + 1, fmt.Errorf("string not terminated")
+			}
+			i++
+			tokens = append(tokens, token{tokenString, line[start:i], start + 1})
+		default:
+			text := ""
+			for _, p := range punct2 {
+				if strings.HasPrefix(line[i:], p) {
+					text = p
+				}
+			}
+			if text == "" && strings.IndexByte(punct1, c) >= 0 {
+				text = line[i : i+1]
+			}
+			if text == "" {
+				return nil, start + 1, fmt.Errorf("unexpected character %q", c)
+			}
+			i += len(text)
+			tokens = append(tokens, token{tokenPunct, text, start + 1})
+		}
+	}
+
+	return tokens, 0, nil
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c == '.' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentChar(c byte) bool {
+	return isIdentStart(c) || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,58 @@
+package chip8
+
+import "time"
+
+// Clock paces the emulation, Cpu sleeps on it to keep the emulated time in sync with the time of clock
+type Clock interface {
+	/*
+		Now should return the time elapsed since the clock was created
+	*/
+	Now() time.Duration
+
+	/*
+		Sleep should block until d has elapsed on the clock
+	*/
+	Sleep(d time.Duration)
+}
+
+// RealClock implements Clock following the wall clock
+type RealClock struct {
+	start time.Time
+}
+
+// NewRealClock returns a pointer to RealClock started now
+func NewRealClock() *RealClock {
+	return &RealClock{start: time.Now()}
+}
+
+// Now returns the time elapsed since the clock was created
+func (rc *RealClock) Now() time.Duration {
+	return time.Since(rc.start)
+}
+
+// Sleep blocks the goroutine for d
+func (rc *RealClock) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+// VirtualClock implements Clock without waiting, its time only advances when Sleep is called.
+// The emulation runs as fast as possible and the timers depend only on the instructions processed,
+// so runs with the same input are deterministic
+type VirtualClock struct {
+	now time.Duration
+}
+
+// NewVirtualClock returns a pointer to VirtualClock on time zero
+func NewVirtualClock() *VirtualClock {
+	return &VirtualClock{}
+}
+
+// Now returns the sum of all durations slept
+func (vc *VirtualClock) Now() time.Duration {
+	return vc.now
+}
+
+// Sleep advances the clock by d, returning immediately
+func (vc *VirtualClock) Sleep(d time.Duration) {
+	vc.now += d
+}
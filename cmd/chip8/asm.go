@@ -0,0 +1,51 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/MarceloMPJR/go-chip-8/asm"
+)
+
+func runAsm(args []string) error {
+	flags := flag.NewFlagSet("asm", flag.ContinueOnError)
+	origin := flags.Uint("origin", 0x200, "address where ROM is loaded")
+	out := flags.String("o", "", "output file, stdout when empty")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		return fmt.Errorf("a source file is required")
+	}
+
+	originAddress, err := address("origin", *origin)
+	if err != nil {
+		return err
+	}
+
+	dir, name := filepath.Split(flags.Arg(0))
+	if dir == "" {
+		dir = "."
+	}
+
+	src, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	assembler := asm.NewAssembler(&asm.ConfigAssembler{FS: os.DirFS(dir), Origin: originAddress})
+	program, err := assembler.Assemble(name, src)
+	if err != nil {
+		return err
+	}
+
+	return writeOutput(*out, func(w io.Writer) error {
+		_, err := w.Write(program.Rom)
+		return err
+	})
+}
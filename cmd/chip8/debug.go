@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"os/signal"
+
+	chip8 "github.com/MarceloMPJR/go-chip-8"
+	"github.com/MarceloMPJR/go-chip-8/debugger"
+)
+
+// runDebug reads the commands of debugger from stdin, the screen is not shown as stdout is the console
+// An interrupt stops the command running and quits, another one kills the process waiting for a command
+func runDebug(args []string) error {
+	flags := flag.NewFlagSet("debug", flag.ContinueOnError)
+	seed := flags.Int64("seed", 1, "seed of random numbers")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		return fmt.Errorf("a ROM file is required")
+	}
+
+	rom, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer rom.Close()
+
+	machine := chip8.NewMachine(chip8.WithRandom(rand.New(rand.NewSource(*seed))))
+	if err := machine.Load(rom); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	dbg := debugger.NewDebugger(&debugger.ConfigDebugger{Cpu: machine.Cpu(), Memory: machine.Memory()})
+	if err := dbg.Console(ctx, os.Stdin, os.Stdout); err != context.Canceled {
+		return err
+	}
+
+	return nil
+}
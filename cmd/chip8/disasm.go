@@ -0,0 +1,50 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/MarceloMPJR/go-chip-8/disasm"
+)
+
+func runDisasm(args []string) error {
+	flags := flag.NewFlagSet("disasm", flag.ContinueOnError)
+	syntax := flags.String("syntax", "cowgod", "syntax of assembly: cowgod or octo")
+	origin := flags.Uint("origin", 0x200, "address where ROM is loaded")
+	comments := flags.Bool("comments", false, "append the address and bytes of each line")
+	out := flags.String("o", "", "output file, stdout when empty")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		return fmt.Errorf("a ROM file is required")
+	}
+
+	config := &disasm.ConfigDisassembler{Comments: *comments}
+
+	var err error
+	if config.Origin, err = address("origin", *origin); err != nil {
+		return err
+	}
+
+	switch *syntax {
+	case "cowgod":
+		config.Syntax = disasm.Cowgod
+	case "octo":
+		config.Syntax = disasm.Octo
+	default:
+		return fmt.Errorf("unknown syntax %q", *syntax)
+	}
+
+	rom, err := os.ReadFile(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	return writeOutput(*out, func(w io.Writer) error {
+		return disasm.NewDisassembler(config).Write(w, rom)
+	})
+}
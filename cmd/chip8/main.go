@@ -0,0 +1,92 @@
+// Command chip8 is a set of tools to CHIP-8 programs
+//
+//	chip8 disasm [flags] rom.ch8
+//	chip8 asm [flags] source.asm
+//	chip8 octo [flags] source.8o
+//	chip8 trace [flags] rom.ch8
+//	chip8 tracediff a.trace b.trace
+//	chip8 screenshot [flags] rom.ch8
+//	chip8 record [flags] rom.ch8
+//	chip8 debug [flags] rom.ch8
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// A command receives its arguments, without its name
+type command struct {
+	name  string
+	usage string
+	run   func(args []string) error
+}
+
+var commands = []command{
+	{"disasm", "disassemble a ROM", runDisasm},
+	{"asm", "assemble a source to ROM", runAsm},
+	{"octo", "compile an Octo source to ROM", runOcto},
+	{"trace", "trace the instructions processed by a ROM", runTrace},
+	{"tracediff", "find the first divergence of two traces", runTraceDiff},
+	{"screenshot", "save the screen of a ROM as PNG", runScreenshot},
+	{"record", "record the screen of a ROM as GIF or Y4M video", runRecord},
+	{"debug", "debug a ROM on a console of stdin and stdout", runDebug},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == os.Args[1] {
+			if err := cmd.run(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "chip8 %s: %s\n", cmd.name, err.Error())
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	usage()
+	os.Exit(2)
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: chip8 <command> [flags] file")
+	fmt.Fprintln(os.Stderr, "commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", cmd.name, cmd.usage)
+	}
+}
+
+// writeOutput calls write with the file named path, or with stdout when path is empty
+// The file is closed returning its error, as a failed write may only be reported by Close
+func writeOutput(path string, write func(w io.Writer) error) (err error) {
+	if path == "" {
+		return write(os.Stdout)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	return write(f)
+}
+
+// address returns the value of flag name as an address, rejecting values beyond 0xFFFF
+func address(name string, value uint) (uint16, error) {
+	if value > 0xFFFF {
+		return 0, fmt.Errorf("-%s 0x%X beyond 0xFFFF", name, value)
+	}
+
+	return uint16(value), nil
+}
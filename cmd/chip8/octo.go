@@ -0,0 +1,77 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/MarceloMPJR/go-chip-8/octo"
+)
+
+func runOcto(args []string) error {
+	flags := flag.NewFlagSet("octo", flag.ContinueOnError)
+	origin := flags.Uint("origin", 0x200, "address where ROM is loaded")
+	out := flags.String("o", "", "output file, stdout when empty")
+	symbols := flags.String("symbols", "", "file where the addresses of labels are written")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		return fmt.Errorf("a source file is required")
+	}
+
+	originAddress, err := address("origin", *origin)
+	if err != nil {
+		return err
+	}
+
+	src, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	compiler := octo.NewCompiler(&octo.ConfigCompiler{Origin: originAddress})
+	program, err := compiler.Compile(flags.Arg(0), src)
+	if err != nil {
+		return err
+	}
+
+	if *symbols != "" {
+		if err := writeSymbols(*symbols, program.Symbols); err != nil {
+			return err
+		}
+	}
+
+	return writeOutput(*out, func(w io.Writer) error {
+		_, err := w.Write(program.Rom)
+		return err
+	})
+}
+
+// writeSymbols writes a line "0xADDR name" by symbol, sorted by address
+func writeSymbols(path string, symbols map[string]uint16) error {
+	names := make([]string, 0, len(symbols))
+	for name := range symbols {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if symbols[names[i]] == symbols[names[j]] {
+			return names[i] < names[j]
+		}
+		return symbols[names[i]] < symbols[names[j]]
+	})
+
+	return writeOutput(path, func(w io.Writer) error {
+		for _, name := range names {
+			if _, err := fmt.Fprintf(w, "0x%03X %s\n", symbols[name], name); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+
+	chip8 "github.com/MarceloMPJR/go-chip-8"
+)
+
+var recordFormats = map[string]chip8.RecordFormat{
+	"gif": chip8.RecordGIF,
+	"y4m": chip8.RecordY4M,
+}
+
+func runRecord(args []string) error {
+	flags := flag.NewFlagSet("record", flag.ContinueOnError)
+	format := flags.String("format", "gif", "format of video: gif or y4m")
+	frames := flags.Int("frames", 600, "frames recorded, at 60 frames per second")
+	scale := flags.Int("scale", 4, "side in pixels of each pixel of display on high resolution")
+	seed := flags.Int64("seed", 1, "seed of random numbers")
+	out := flags.String("o", "", "output file, stdout when empty")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		return fmt.Errorf("a ROM file is required")
+	}
+
+	recordFormat, ok := recordFormats[*format]
+	if !ok {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	rom, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer rom.Close()
+
+	display := chip8.NewStandardDisplay(&chip8.ConfigDisplay{Output: io.Discard})
+	recorder := chip8.NewRecorder(&chip8.ConfigRecorder{
+		Framebuffer: display,
+		Format:      recordFormat,
+		Image:       chip8.ConfigImage{Scale: *scale},
+	})
+
+	machine := chip8.NewMachine(
+		chip8.WithRandom(rand.New(rand.NewSource(*seed))),
+		chip8.WithDisplay(display),
+		chip8.WithHooks(chip8.Hooks{DisplayFlush: recorder.Flush, FrameEnd: recorder.FrameEnd}),
+	)
+	if err := machine.Load(rom); err != nil {
+		return err
+	}
+
+	return writeOutput(*out, func(w io.Writer) error {
+		if err := recorder.Start(w); err != nil {
+			return err
+		}
+
+		cpu := machine.Cpu()
+		for i := 0; i < *frames && !cpu.Halted(); i++ {
+			if err := cpu.RunFrame(); err != nil {
+				recorder.Stop()
+				return err
+			}
+		}
+
+		return recorder.Stop()
+	})
+}
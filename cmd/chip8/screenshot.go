@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+
+	chip8 "github.com/MarceloMPJR/go-chip-8"
+)
+
+func runScreenshot(args []string) error {
+	flags := flag.NewFlagSet("screenshot", flag.ContinueOnError)
+	frames := flags.Int("frames", 60, "frames run before the screenshot, at 60 frames per second")
+	scale := flags.Int("scale", 8, "side in pixels of each pixel of display")
+	seed := flags.Int64("seed", 1, "seed of random numbers")
+	out := flags.String("o", "", "output PNG file, stdout when empty")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		return fmt.Errorf("a ROM file is required")
+	}
+
+	rom, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer rom.Close()
+
+	machine := chip8.NewMachine(chip8.WithRandom(rand.New(rand.NewSource(*seed))))
+	if err := machine.Load(rom); err != nil {
+		return err
+	}
+
+	cpu := machine.Cpu()
+	for i := 0; i < *frames && !cpu.Halted(); i++ {
+		if err := cpu.RunFrame(); err != nil {
+			return err
+		}
+	}
+
+	framebuffer := machine.Display().(chip8.Framebuffer)
+	return writeOutput(*out, func(w io.Writer) error {
+		return chip8.Screenshot(w, framebuffer, &chip8.ConfigImage{Scale: *scale})
+	})
+}
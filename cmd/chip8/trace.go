@@ -0,0 +1,126 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+
+	chip8 "github.com/MarceloMPJR/go-chip-8"
+	"github.com/MarceloMPJR/go-chip-8/trace"
+)
+
+var formats = map[string]trace.Format{
+	"text":   trace.Text,
+	"jsonl":  trace.JSONLines,
+	"binary": trace.Binary,
+}
+
+func runTrace(args []string) error {
+	flags := flag.NewFlagSet("trace", flag.ContinueOnError)
+	format := flags.String("format", "text", "format of trace: text, jsonl or binary")
+	frames := flags.Int("frames", 600, "frames run, at 60 frames per second")
+	from := flags.Uint("from", 0, "first address traced")
+	to := flags.Uint("to", 0xFFFF, "last address traced")
+	class := flags.String("class", "", "classes traced separated by commas: flow, arithmetic, memory, display, timer, input")
+	seed := flags.Int64("seed", 1, "seed of random numbers")
+	out := flags.String("o", "", "output file, stdout when empty")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		return fmt.Errorf("a ROM file is required")
+	}
+
+	config := &trace.ConfigTracer{}
+
+	var ok bool
+	if config.Format, ok = formats[*format]; !ok {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	var err error
+	if config.From, err = address("from", *from); err != nil {
+		return err
+	}
+	if config.To, err = address("to", *to); err != nil {
+		return err
+	}
+	if config.Classes, err = trace.ParseClass(*class); err != nil {
+		return err
+	}
+
+	rom, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer rom.Close()
+
+	return writeOutput(*out, func(w io.Writer) error {
+		config.Output = w
+		tracer := trace.NewTracer(config)
+
+		machine := chip8.NewMachine(chip8.WithRandom(rand.New(rand.NewSource(*seed))), chip8.WithTracer(tracer))
+		if err := machine.Load(rom); err != nil {
+			return err
+		}
+
+		cpu := machine.Cpu()
+		for i := 0; i < *frames && !cpu.Halted(); i++ {
+			if err := cpu.RunFrame(); err != nil {
+				return err
+			}
+		}
+
+		return tracer.Err()
+	})
+}
+
+// runTraceDiff accepts traces of any format, text, jsonl or binary, detected from their first bytes
+func runTraceDiff(args []string) error {
+	flags := flag.NewFlagSet("tracediff", flag.ContinueOnError)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 2 {
+		return fmt.Errorf("two trace files are required")
+	}
+
+	a, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	b, err := os.Open(flags.Arg(1))
+	if err != nil {
+		return err
+	}
+	defer b.Close()
+
+	divergence, err := trace.Diff(trace.NewReader(a), trace.NewReader(b))
+	if err != nil {
+		return err
+	}
+	if divergence == nil {
+		return nil
+	}
+
+	fmt.Printf("traces diverge at entry %d\n", divergence.Index)
+	for _, side := range []struct {
+		prefix string
+		entry  *trace.Entry
+	}{{"<", divergence.A}, {">", divergence.B}} {
+		if side.entry == nil {
+			fmt.Printf("%s end of trace\n", side.prefix)
+		} else {
+			fmt.Printf("%s %s\n", side.prefix, side.entry)
+		}
+	}
+
+	return errors.New("traces differ")
+}
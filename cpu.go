@@ -1,23 +1,54 @@
 package chip8
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"io"
-	"math/rand"
+	"sync"
 	"time"
 )
 
+const defaultInstructionsPerFrame = 8
+const defaultEntry = 0x200
+const framesPerSecond = 60
+
+// maxFrameLag is how many frames Start may fall behind its clock before giving up catching up
+const maxFrameLag = 5
+
 type Register [0x10]byte
 type Stack [0x10]uint16
 
+// Cpu is safe for concurrent use: each instruction is processed holding a lock, that Start
+// releases between frames. The devices are called holding the lock and must not call Cpu
 type Cpu struct {
+	mu       sync.Mutex
 	display  Display
 	keyboard Keyboard
 	sound    Sound
 	memory   Memory
+	random   Random
 	register Register
 	stack    Stack
+	flags    Register
+	pattern  [patternSize]byte
+	pitch    byte
+	planes   byte
 	log      io.Writer
+	quirks   Quirks
+	set      InstructionSet
+	ipf      int
+	clock    Clock
+	tracer   Tracer
+	hooks    Hooks
+	entry    uint16
+	turbo    bool
+	paused   bool
+	resume   chan struct{}
+	frames   uint64
+	cycles   int
+	halted   bool
+	err      error
 	sp       byte
 	dt       byte
 	st       byte
@@ -25,6 +56,26 @@ type Cpu struct {
 	i        uint16
 }
 
+// State is a snapshot of the registers of Cpu
+type State struct {
+	Register Register
+	Stack    Stack
+	Flags    Register
+	PC       uint16
+	I        uint16
+	SP       byte
+	DT       byte
+	ST       byte
+	Planes   byte
+	Halted   bool
+}
+
+// Tracer receives each instruction processed by Cpu, with the states before and after it
+// Trace is called holding the lock of Cpu, also when the instruction fails
+type Tracer interface {
+	Trace(instr Instruction, before, after State)
+}
+
 type ConfigCpu struct {
 	// Externals devices
 	Display  Display
@@ -34,11 +85,34 @@ type ConfigCpu struct {
 	Stack    Stack
 	Log      io.Writer
 
+	// Source of instruction CXNN, when nil it is the global source of math/rand
+	Random Random
+
+	// Behavior of ambiguous instructions
+	Quirks Quirks
+
+	// Instructions accepted, when zero every instruction supported by the devices
+	Instructions InstructionSet
+
+	// Instructions processed by frame, when zero it is 8
+	// The timers are decremented once by frame, at 60 frames per second of emulated time
+	InstructionsPerFrame int
+
+	// Clock paces Start, when nil it is a RealClock
+	Clock Clock
+
+	// Tracer receives each instruction processed, when nil nothing is traced
+	Tracer Tracer
+
+	// Callbacks of events, see SetHooks
+	Hooks Hooks
+
 	// Registers
 	Register Register
 	I        uint16
 
 	// Pseudo Registers
+	// PC is also the address where Reset starts the program, 0x200 when zero
 	PC uint16
 	SP byte
 	DT byte
@@ -47,14 +121,44 @@ type ConfigCpu struct {
 
 // NewCpu receives params and return a pointer to Cpu
 func NewCpu(config *ConfigCpu) *Cpu {
+	ipf := config.InstructionsPerFrame
+	if ipf <= 0 {
+		ipf = defaultInstructionsPerFrame
+	}
+
+	clock := config.Clock
+	if clock == nil {
+		clock = NewRealClock()
+	}
+
+	entry := config.PC
+	if entry == 0 {
+		entry = defaultEntry
+	}
+
+	random := config.Random
+	if random == nil {
+		random = globalRandom{}
+	}
+
 	return &Cpu{
 		display:  config.Display,
 		keyboard: config.Keyboard,
 		sound:    config.Sound,
 		memory:   config.Memory,
+		random:   random,
 		register: config.Register,
 		stack:    config.Stack,
 		log:      config.Log,
+		quirks:   config.Quirks,
+		set:      config.Instructions,
+		ipf:      ipf,
+		clock:    clock,
+		tracer:   config.Tracer,
+		hooks:    config.Hooks,
+		entry:    entry,
+		pitch:    defaultPitch,
+		planes:   0x1,
 		pc:       config.PC,
 		i:        config.I,
 		sp:       config.SP,
@@ -63,64 +167,494 @@ func NewCpu(config *ConfigCpu) *Cpu {
 	}
 }
 
-// Start the interpreter
-func (c *Cpu) Start() {
-	go c.startTimer()
-	c.startInterpreter()
+// Start the interpreter, returns when Cpu is halted with the error that halted it
+// Instruction 00FD halts Cpu without error
+// Each frame is processed at once and Start sleeps on clock until the time of next frame
+// When ctx is done the interpreter is stopped and ctx.Err() is returned,
+// Cpu keeps its state and can be started again
+// While Cpu is paused Start waits for Resume, see Pause
+func (c *Cpu) Start(ctx context.Context) error {
+	start := c.clock.Now()
+	frames := int64(0)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		c.mu.Lock()
+		if c.paused {
+			resume := c.resume
+			c.mu.Unlock()
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-resume:
+			}
+
+			// The time paused is not emulated, restarts counting from now
+			start, frames = c.clock.Now(), 0
+			continue
+		}
+
+		err := c.runFrame()
+		halted := c.halted
+		turbo := c.turbo
+		c.mu.Unlock()
+
+		if err != nil {
+			return err
+		}
+		if halted {
+			return nil
+		}
+
+		if turbo {
+			start, frames = c.clock.Now(), 0
+			continue
+		}
+
+		frames++
+		next := start + time.Duration(frames)*time.Second/framesPerSecond
+		lag := c.clock.Now() - next
+		if lag < 0 {
+			c.clock.Sleep(-lag)
+		} else if lag > maxFrameLag*time.Second/framesPerSecond {
+			// Too slow to catch up, restarts counting from now
+			start, frames = c.clock.Now(), 0
+		}
+	}
 }
 
 // Log writes values of registers to "log" of Cpu
 func (c *Cpu) Log() {
+	c.mu.Lock()
 	str := fmt.Sprintf("pc = %x\nsp = %x\ndt = %x\nst = %x\ni = %x\nstack = %v\n", c.pc, c.sp, c.dt, c.st, c.i, c.stack)
 
 	for i := 0; i < len(c.register); i++ {
 		str += fmt.Sprintf("register[%d] = %x\n", i, c.register[i])
 	}
+	c.mu.Unlock()
 
 	c.log.Write([]byte(str))
 }
 
 // Process is a function that process a instruction
+// When the instruction fails Cpu is halted and the error is returned
 func (c *Cpu) Process(instr Instruction) error {
-	if err := c.handle(instr); err != nil {
-		return err
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.process(instr)
+}
+
+// Step fetches the instruction addressed by PC from memory and processes it
+// After the last instruction of a frame the timers are decremented
+func (c *Cpu) Step() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.step()
+}
+
+// RunCycles processes n instructions, stopping earlier when Cpu is halted
+func (c *Cpu) RunCycles(n int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i := 0; i < n; i++ {
+		if err := c.step(); err != nil {
+			return err
+		}
+
+		if c.halted {
+			return nil
+		}
 	}
 
 	return nil
 }
 
+// RunFrame processes the instructions until the end of current frame, decrementing the timers once
+func (c *Cpu) RunFrame() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.runFrame()
+}
+
+// SetSpeed sets the instructions processed by second, rounded to a multiple of 60 frames per second
+// When it is zero the speed is unlimited: Start processes the frames without waiting the clock
+func (c *Cpu) SetSpeed(instructionsPerSecond int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if instructionsPerSecond <= 0 {
+		c.turbo = true
+		return
+	}
+
+	c.turbo = false
+	c.ipf = instructionsPerSecond / framesPerSecond
+	if c.ipf < 1 {
+		c.ipf = 1
+	}
+}
+
+// Speed returns the instructions processed by second, zero when it is unlimited
+func (c *Cpu) Speed() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.turbo {
+		return 0
+	}
+
+	return c.ipf * framesPerSecond
+}
+
+// Pause makes Start wait for Resume after the current frame, so the timers are frozen too
+// A paused Cpu can still be driven by Step, RunCycles, RunFrame and FrameAdvance
+func (c *Cpu) Pause() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.pause()
+}
+
+// Resume continues Start after Pause
+func (c *Cpu) Resume() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.paused {
+		return
+	}
+
+	c.paused = false
+	close(c.resume)
+}
+
+// Paused returns true when Cpu is paused
+func (c *Cpu) Paused() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.paused
+}
+
+// FrameAdvance pauses Cpu and processes the instructions until the end of current frame
+func (c *Cpu) FrameAdvance() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.pause()
+	return c.runFrame()
+}
+
+// Elapsed returns the emulated time, that advances 1/60 second for each frame processed
+func (c *Cpu) Elapsed() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return time.Duration(c.frames)*time.Second/framesPerSecond +
+		time.Duration(c.cycles)*time.Second/time.Duration(framesPerSecond*c.ipf)
+}
+
 func (c *Cpu) NextInstruction() uint16 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	return c.pc
 }
 
-func (c *Cpu) startTimer() {
-	for {
-		if c.dt > 0 {
-			c.dt--
+// Halted returns true when Cpu stopped by instruction 00FD or by an error
+func (c *Cpu) Halted() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.halted
+}
+
+// Err returns the error that halted Cpu, nil when it is running or stopped by instruction 00FD
+func (c *Cpu) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.err
+}
+
+// SetHooks replaces the callbacks of events, Hooks{} removes all of them
+func (c *Cpu) SetHooks(hooks Hooks) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.hooks = hooks
+}
+
+// State returns a snapshot of the registers of Cpu
+func (c *Cpu) State() State {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.state()
+}
+
+// Reset restores the power-on state: registers, stack and timers cleared and PC on the start of program
+// The display is cleared and the memory reloaded with fonts and ROM, when they are resettable
+func (c *Cpu) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.reset()
+}
+
+// LoadROM replaces the program on memory, that must be a ResettableMemory, and resets Cpu
+// When it fails Cpu and memory are not changed
+func (c *Cpu) LoadROM(rom io.Reader) error {
+	// The memory is set only by NewCpu, so it is checked without the lock
+	memory, ok := c.memory.(ResettableMemory)
+	if !ok {
+		return ErrNotResettable
+	}
+
+	// The ROM is read before locking, so a slow reader does not block Cpu
+	// A byte beyond the largest memory is kept for the memory to reject the ROM
+	data, err := io.ReadAll(io.LimitReader(rom, XOChipMemorySize+1))
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := memory.LoadROM(bytes.NewReader(data)); err != nil {
+		return err
+	}
+
+	c.reset()
+	return nil
+}
+
+// SetState replaces the registers of Cpu by state, that is validated before any change
+// Halted set to false resumes a halted Cpu, clearing its error
+func (c *Cpu) SetState(state State) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.validateState(state.PC, state.I, state.SP, state.Stack, state.Planes); err != nil {
+		return err
+	}
+
+	c.register = state.Register
+	c.stack = state.Stack
+	c.flags = state.Flags
+	c.pc = state.PC
+	c.i = state.I
+	c.sp = state.SP
+	c.dt = state.DT
+	c.st = state.ST
+	if display, ok := c.display.(PlaneDisplay); ok && c.planes != state.Planes {
+		display.SelectPlanes(state.Planes)
+	}
+	c.planes = state.Planes
+	c.halted = state.Halted
+	c.err = nil
+
+	return nil
+}
+
+// SetPC sets the address of next instruction
+func (c *Cpu) SetPC(pc uint16) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.validateAddress("pc", pc, 2); err != nil {
+		return err
+	}
+
+	c.pc = pc
+	return nil
+}
+
+// SetI sets the register I
+func (c *Cpu) SetI(i uint16) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.validateAddress("i", i, 1); err != nil {
+		return err
+	}
+
+	c.i = i
+	return nil
+}
+
+// SetRegister sets the register VX
+func (c *Cpu) SetRegister(x, value byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if int(x) >= len(c.register) {
+		return fmt.Errorf("%w: register %d", ErrOutOfRange, x)
+	}
+
+	c.register[x] = value
+	return nil
+}
+
+func (c *Cpu) state() State {
+	return State{
+		Register: c.register,
+		Stack:    c.stack,
+		Flags:    c.flags,
+		PC:       c.pc,
+		I:        c.i,
+		SP:       c.sp,
+		DT:       c.dt,
+		ST:       c.st,
+		Planes:   c.planes,
+		Halted:   c.halted,
+	}
+}
+
+func (c *Cpu) process(instr Instruction) error {
+	if c.halted {
+		return ErrHalted
+	}
+
+	if c.tracer == nil && c.hooks.BeforeInstruction == nil && c.hooks.AfterInstruction == nil {
+		if err := c.handle(instr); err != nil {
+			c.halt(err)
+			return err
 		}
 
-		if c.st > 0 {
-			c.st--
-			if c.st == 0 {
-				c.sound.Beep()
-			}
+		return nil
+	}
+
+	before := c.state()
+	if c.hooks.BeforeInstruction != nil {
+		c.hooks.BeforeInstruction(instr, before)
+	}
+
+	err := c.handle(instr)
+	if err != nil {
+		c.halt(err)
+	}
+
+	after := c.state()
+	if c.tracer != nil {
+		c.tracer.Trace(instr, before, after)
+	}
+	if c.hooks.AfterInstruction != nil {
+		c.hooks.AfterInstruction(instr, after, err)
+	}
+
+	return err
+}
+
+func (c *Cpu) step() error {
+	if c.halted {
+		return ErrHalted
+	}
+
+	if err := c.checkMemory(c.pc, 2); err != nil {
+		c.halt(err)
+		return err
+	}
+
+	if err := c.process(c.memory.LoadInstruction(c.pc)); err != nil {
+		return err
+	}
+
+	c.cycles++
+	if c.cycles >= c.ipf {
+		c.cycles = 0
+		c.frames++
+		c.tickTimers()
+		if c.hooks.FrameEnd != nil {
+			c.hooks.FrameEnd(c.frames)
 		}
+	}
 
-		time.Sleep(16 * time.Millisecond)
+	return nil
+}
+
+func (c *Cpu) runFrame() error {
+	frame := c.frames
+	for c.frames == frame {
+		if err := c.step(); err != nil {
+			return err
+		}
+
+		if c.halted {
+			return nil
+		}
 	}
+
+	return nil
 }
 
-func (c *Cpu) startInterpreter() {
-	for {
-		pc := c.NextInstruction()
-		instr := c.memory.LoadInstruction(pc)
-		c.Process(instr)
+func (c *Cpu) reset() {
+	c.register = Register{}
+	c.stack = Stack{}
+	c.flags = Register{}
+	c.pattern = [patternSize]byte{}
+	c.pitch = defaultPitch
+	c.planes = 0x1
+	c.frames, c.cycles = 0, 0
+	c.halted, c.err = false, nil
+	c.sp, c.dt, c.st = 0, 0, 0
+	c.pc, c.i = c.entry, 0
+
+	if memory, ok := c.memory.(ResettableMemory); ok {
+		memory.Reset()
+	}
+
+	if display, ok := c.display.(ResettableDisplay); ok {
+		display.Reset()
+		c.flush()
+	}
+
+	if sound, ok := c.sound.(PatternSound); ok {
+		sound.SetPattern(c.pattern)
+		sound.SetPitch(c.pitch)
+		sound.SetPlaying(false)
 	}
 }
 
-func (c *Cpu) handle(instr Instruction) error {
-	time.Sleep(2 * time.Millisecond)
+func (c *Cpu) pause() {
+	if c.paused {
+		return
+	}
+
+	c.paused = true
+	c.resume = make(chan struct{})
+}
+
+func (c *Cpu) halt(err error) {
+	c.halted = true
+	c.err = err
+}
+
+// tickTimers decrements DT and ST, when ST reaches zero the sound beeps
+func (c *Cpu) tickTimers() {
+	if c.dt > 0 {
+		c.setTimer(TimerDelay, c.dt-1)
+	}
+
+	if c.st > 0 {
+		c.setTimer(TimerSound, c.st-1)
+		if c.st == 0 {
+			c.sound.Beep()
+		}
+	}
+}
 
+func (c *Cpu) handle(instr Instruction) error {
 	x, err := instr.GetX()
 	if err != nil {
 		return err
@@ -153,28 +687,46 @@ func (c *Cpu) handle(instr Instruction) error {
 
 	switch instrType {
 	case InstructionType(0x00):
-		switch nn {
-		case 0xE0:
+		switch {
+		case nn == 0xE0:
 			c.process0x00E0()
-		case 0xEE:
-			c.process0x00EE()
+		case nn == 0xEE:
+			return c.process0x00EE()
+		case nn&0xF0 == 0xC0 && c.isHiRes():
+			c.process0x00CN(n)
+		case nn&0xF0 == 0xD0 && c.isHiRes() && c.supports(InstructionsXOCHIP):
+			c.process0x00DN(n)
+		case nn == 0xFB && c.isHiRes():
+			c.process0x00FB()
+		case nn == 0xFC && c.isHiRes():
+			c.process0x00FC()
+		case nn == 0xFD && c.supports(InstructionsSCHIP):
+			c.process0x00FD()
+		case nn == 0xFE && c.isHiRes():
+			c.process0x00FE()
+		case nn == 0xFF && c.isHiRes():
+			c.process0x00FF()
 		default:
-			panic("invalid instruction")
+			return c.unknownOpcode(instr)
 		}
 	case InstructionType(0x01):
 		c.process0x1NNN(nnn)
 	case InstructionType(0x02):
-		c.process0x2NNN(nnn)
+		return c.process0x2NNN(nnn)
 	case InstructionType(0x03):
 		c.process0x3XNN(x, nn)
 	case InstructionType(0x04):
 		c.process0x4XNN(x, nn)
 	case InstructionType(0x05):
-		switch instrSubtype {
-		case InstructionSubType(0x00):
+		switch {
+		case instrSubtype == InstructionSubType(0x00):
 			c.process0x5XY0(x, y)
+		case instrSubtype == InstructionSubType(0x02) && c.supports(InstructionsXOCHIP):
+			return c.process0x5XY2(x, y)
+		case instrSubtype == InstructionSubType(0x03) && c.supports(InstructionsXOCHIP):
+			return c.process0x5XY3(x, y)
 		default:
-			panic("invalid instruction")
+			return c.unknownOpcode(instr)
 		}
 	case InstructionType(0x06):
 		c.process0x6XNN(x, nn)
@@ -201,23 +753,23 @@ func (c *Cpu) handle(instr Instruction) error {
 		case InstructionSubType(0x0E):
 			c.process0x8XYE(x, y)
 		default:
-			panic("invalid instruction")
+			return c.unknownOpcode(instr)
 		}
 	case InstructionType(0x09):
 		switch instrSubtype {
 		case InstructionSubType(0x00):
 			c.process0x9XY0(x, y)
 		default:
-			panic("invalid instruction")
+			return c.unknownOpcode(instr)
 		}
 	case InstructionType(0x0A):
 		c.process0xANNN(nnn)
 	case InstructionType(0x0B):
-		c.process0xBNNN(nnn)
+		c.process0xBNNN(x, nnn)
 	case InstructionType(0x0C):
 		c.process0xCXNN(x, nn)
 	case InstructionType(0x0D):
-		c.process0xDXYN(x, y, n)
+		return c.process0xDXYN(x, y, n)
 	case InstructionType(0x0E):
 		switch nn {
 		case 0x9E:
@@ -225,33 +777,47 @@ func (c *Cpu) handle(instr Instruction) error {
 		case 0xA1:
 			c.process0xEXA1(x)
 		default:
-			panic("invalid instruction")
+			return c.unknownOpcode(instr)
 		}
 	case InstructionType(0x0F):
-		switch nn {
-		case 0x07:
+		switch {
+		case x == 0x00 && nn == 0x00 && c.supports(InstructionsXOCHIP):
+			return c.process0xF000()
+		case nn == 0x01 && c.hasPlanes():
+			c.process0xFN01(x)
+		case x == 0x00 && nn == 0x02 && c.supports(InstructionsXOCHIP):
+			return c.process0xF002()
+		case nn == 0x07:
 			c.process0xFX07(x)
-		case 0x0A:
+		case nn == 0x0A:
 			c.process0xFX0A(x)
-		case 0x15:
+		case nn == 0x15:
 			c.process0xFX15(x)
-		case 0x18:
+		case nn == 0x18:
 			c.process0xFX18(x)
-		case 0x1E:
+		case nn == 0x1E:
 			c.process0xFX1E(x)
-		case 0x29:
+		case nn == 0x29:
 			c.process0xFX29(x)
-		case 0x33:
-			c.process0xFX33(x)
-		case 0x55:
-			c.process0xFX55(x)
-		case 0x65:
-			c.process0xFX65(x)
+		case nn == 0x30 && c.hasLargeFont():
+			c.process0xFX30(x)
+		case nn == 0x33:
+			return c.process0xFX33(x)
+		case nn == 0x3A && c.supports(InstructionsXOCHIP):
+			c.process0xFX3A(x)
+		case nn == 0x55:
+			return c.process0xFX55(x)
+		case nn == 0x65:
+			return c.process0xFX65(x)
+		case nn == 0x75 && c.supports(InstructionsSCHIP):
+			c.process0xFX75(x)
+		case nn == 0x85 && c.supports(InstructionsSCHIP):
+			c.process0xFX85(x)
 		default:
-			panic("invalid instruction")
+			return c.unknownOpcode(instr)
 		}
 	default:
-		panic("invalid instruction")
+		return c.unknownOpcode(instr)
 	}
 
 	return nil
@@ -259,23 +825,76 @@ func (c *Cpu) handle(instr Instruction) error {
 
 func (c *Cpu) process0x00E0() {
 	c.display.Clear()
-	c.display.Flush()
+	if c.hooks.DisplayClear != nil {
+		c.hooks.DisplayClear()
+	}
+	c.flush()
 	c.pc += 2
 }
 
-func (c *Cpu) process0x00EE() {
+func (c *Cpu) process0x00EE() error {
+	if c.sp == 0 {
+		return fmt.Errorf("%w at 0x%03X", ErrStackUnderflow, c.pc)
+	}
+
 	c.sp--
 	c.pc = c.stack[c.sp]
+	return nil
+}
+
+func (c *Cpu) process0x00CN(n byte) {
+	c.display.(HiResDisplay).ScrollDown(n)
+	c.flush()
+	c.pc += 2
+}
+
+func (c *Cpu) process0x00DN(n byte) {
+	c.display.(HiResDisplay).ScrollUp(n)
+	c.flush()
+	c.pc += 2
+}
+
+func (c *Cpu) process0x00FB() {
+	c.display.(HiResDisplay).ScrollRight()
+	c.flush()
+	c.pc += 2
+}
+
+func (c *Cpu) process0x00FC() {
+	c.display.(HiResDisplay).ScrollLeft()
+	c.flush()
+	c.pc += 2
+}
+
+func (c *Cpu) process0x00FD() {
+	c.halted = true
+}
+
+func (c *Cpu) process0x00FE() {
+	c.display.(HiResDisplay).SetHighResolution(false)
+	c.flush()
+	c.pc += 2
+}
+
+func (c *Cpu) process0x00FF() {
+	c.display.(HiResDisplay).SetHighResolution(true)
+	c.flush()
+	c.pc += 2
 }
 
 func (c *Cpu) process0x1NNN(nnn uint16) {
 	c.pc = nnn
 }
 
-func (c *Cpu) process0x2NNN(nnn uint16) {
+func (c *Cpu) process0x2NNN(nnn uint16) error {
+	if int(c.sp) >= len(c.stack) {
+		return fmt.Errorf("%w at 0x%03X", ErrStackOverflow, c.pc)
+	}
+
 	c.stack[c.sp] = c.pc + 0x2
 	c.sp++
 	c.pc = nnn
+	return nil
 }
 
 func (c *Cpu) process0x3XNN(x, nn byte) {
@@ -283,7 +902,7 @@ func (c *Cpu) process0x3XNN(x, nn byte) {
 		c.pc += 2
 		return
 	}
-	c.pc += 4
+	c.skip()
 }
 
 func (c *Cpu) process0x4XNN(x, nn byte) {
@@ -291,7 +910,7 @@ func (c *Cpu) process0x4XNN(x, nn byte) {
 		c.pc += 2
 		return
 	}
-	c.pc += 4
+	c.skip()
 }
 
 func (c *Cpu) process0x5XY0(x, y byte) {
@@ -299,7 +918,39 @@ func (c *Cpu) process0x5XY0(x, y byte) {
 		c.pc += 2
 		return
 	}
-	c.pc += 4
+	c.skip()
+}
+
+func (c *Cpu) process0x5XY2(x, y byte) error {
+	register := c.registerRange(x, y)
+	if err := c.checkMemory(c.i, len(register)); err != nil {
+		return err
+	}
+
+	c.memory.Save(register, c.i)
+	c.memoryWritten(c.i, register)
+	c.pc += 2
+	return nil
+}
+
+func (c *Cpu) process0x5XY3(x, y byte) error {
+	register := make([]byte, len(c.registerRange(x, y)))
+	if err := c.checkMemory(c.i, len(register)); err != nil {
+		return err
+	}
+
+	c.memory.Load(register, c.i)
+	c.memoryRead(c.i, register)
+
+	for idx, reg := range register {
+		if x <= y {
+			c.register[int(x)+idx] = reg
+		} else {
+			c.register[int(x)-idx] = reg
+		}
+	}
+	c.pc += 2
+	return nil
 }
 
 func (c *Cpu) process0x6XNN(x, nn byte) {
@@ -319,64 +970,81 @@ func (c *Cpu) process0x8XY0(x, y byte) {
 
 func (c *Cpu) process0x8XY1(x, y byte) {
 	c.register[x] |= c.register[y]
+	if c.quirks.VFReset {
+		c.register[0xF] = 0x00
+	}
 	c.pc += 2
 }
 
 func (c *Cpu) process0x8XY2(x, y byte) {
 	c.register[x] &= c.register[y]
+	if c.quirks.VFReset {
+		c.register[0xF] = 0x00
+	}
 	c.pc += 2
 }
 
 func (c *Cpu) process0x8XY3(x, y byte) {
 	c.register[x] ^= c.register[y]
+	if c.quirks.VFReset {
+		c.register[0xF] = 0x00
+	}
 	c.pc += 2
 }
 
+// The flag is written after the result, it is kept when X is F
 func (c *Cpu) process0x8XY4(x, y byte) {
-	val := c.register[x] + c.register[y]
-
-	c.register[0xF] = 0x00
+	flag := byte(0x00)
 	if int(c.register[x])+int(c.register[y]) > 0xFF {
-		c.register[0xF] = 0x01
+		flag = 0x01
 	}
 
-	c.register[x] = val
+	c.register[x] += c.register[y]
+	c.register[0xF] = flag
 	c.pc += 2
 }
 
 func (c *Cpu) process0x8XY5(x, y byte) {
-	val := c.register[x] - c.register[y]
-
-	c.register[0xF] = 0x00
-	if int(c.register[x])-int(c.register[y]) >= 0x0 {
-		c.register[0xF] = 0x01
+	flag := byte(0x00)
+	if c.register[x] >= c.register[y] {
+		flag = 0x01
 	}
 
-	c.register[x] = val
+	c.register[x] -= c.register[y]
+	c.register[0xF] = flag
 	c.pc += 2
 }
 
 func (c *Cpu) process0x8XY6(x, y byte) {
-	c.register[0xF] = c.register[x] & 0x01
+	if c.quirks.ShiftUsesVY {
+		c.register[x] = c.register[y]
+	}
+
+	flag := c.register[x] & 0x01
 	c.register[x] >>= 1
+	c.register[0xF] = flag
 	c.pc += 2
 }
 
 func (c *Cpu) process0x8XY7(x, y byte) {
-	val := c.register[y] - c.register[x]
-
-	c.register[0xF] = 0x00
-	if int(c.register[y])-int(c.register[x]) >= 0x0 {
-		c.register[0xF] = 0x01
+	flag := byte(0x00)
+	if c.register[y] >= c.register[x] {
+		flag = 0x01
 	}
 
-	c.register[x] = val
+	c.register[x] = c.register[y] - c.register[x]
+	c.register[0xF] = flag
 	c.pc += 2
 }
 
 func (c *Cpu) process0x8XYE(x, y byte) {
-	c.register[0xF] = (c.register[x] >> 7)
+	if c.quirks.ShiftUsesVY {
+		c.register[x] = c.register[y]
+	}
+
+	flag := c.register[x] >> 7
 	c.register[x] <<= 1
+	c.register[0xF] = flag
 	c.pc += 2
 }
 
@@ -385,7 +1053,7 @@ func (c *Cpu) process0x9XY0(x, y byte) {
 		c.pc += 2
 		return
 	}
-	c.pc += 4
+	c.skip()
 }
 
 func (c *Cpu) process0xANNN(nnn uint16) {
@@ -393,21 +1061,58 @@ func (c *Cpu) process0xANNN(nnn uint16) {
 	c.pc += 2
 }
 
-func (c *Cpu) process0xBNNN(nnn uint16) {
+func (c *Cpu) process0xBNNN(x byte, nnn uint16) {
+	if c.quirks.JumpUsesVX {
+		c.pc = nnn + uint16(c.register[x])
+		return
+	}
 	c.pc = nnn + uint16(c.register[0])
 }
 
 func (c *Cpu) process0xCXNN(x, nn byte) {
-	c.register[x] = byte(rand.Intn(0xFF)) & nn
+	c.register[x] = byte(c.random.Intn(0x100)) & nn
 	c.pc += 2
 }
 
-func (c *Cpu) process0xDXYN(x, y, n byte) {
-	xDisplay, yDisplay := c.register[x], c.register[y]
+func (c *Cpu) process0xDXYN(x, y, n byte) error {
+	width, height := c.screenSize()
+	xDisplay, yDisplay := int(c.register[x])%width, int(c.register[y])%height
+
+	// DXY0 draws a sprite of 16x16 on SUPER-CHIP
+	rows, cols := int(n), 1
+	if n == 0 && c.isHiRes() {
+		rows, cols = 16, 2
+	}
+
+	planes := c.drawingPlanes()
+	if err := c.checkMemory(c.i, len(planes)*rows*cols); err != nil {
+		return err
+	}
+
 	colission := false
-	for i := 0; i < int(n); i++ {
-		sprite := c.memory.LoadSprite(c.i + uint16(i))
-		colission = c.display.Draw(xDisplay, yDisplay+byte(i), sprite) || colission
+	addr := c.i
+	for _, plane := range planes {
+		for row := 0; row < rows; row++ {
+			for col := 0; col < cols; col++ {
+				sprite := c.memory.LoadSprite(addr)
+				if c.hooks.MemoryRead != nil {
+					c.hooks.MemoryRead(addr, []byte{sprite})
+				}
+				addr++
+
+				xSprite, ySprite := xDisplay+col*8, yDisplay+row
+				if c.quirks.Clipping {
+					if xSprite >= width || ySprite >= height {
+						continue
+					}
+					if xSprite+8 > width {
+						// Drop the pixels beyond the right edge
+						sprite &= byte(0xFF << (xSprite + 8 - width))
+					}
+				}
+				colission = c.drawSprite(plane, byte(xSprite), byte(ySprite), sprite) || colission
+			}
+		}
 	}
 
 	c.register[0xF] = 0x00
@@ -415,24 +1120,59 @@ func (c *Cpu) process0xDXYN(x, y, n byte) {
 		c.register[0xF] = 0x01
 	}
 
-	c.display.Flush()
+	c.flush()
 	c.pc += 2
+	return nil
 }
 
 func (c *Cpu) process0xEX9E(x byte) {
-	if Key(c.register[x]) != c.keyboard.KeyDown() {
+	if Key(c.register[x]) != c.keyDown() {
 		c.pc += 2
 		return
 	}
-	c.pc += 4
+	c.skip()
 }
 
 func (c *Cpu) process0xEXA1(x byte) {
-	if Key(c.register[x]) == c.keyboard.KeyDown() {
+	if Key(c.register[x]) == c.keyDown() {
 		c.pc += 2
 		return
 	}
+	c.skip()
+}
+
+func (c *Cpu) process0xF000() error {
+	if err := c.checkMemory(c.pc+2, 2); err != nil {
+		return err
+	}
+
+	nnnn := c.memory.LoadInstruction(c.pc + 2)
+	c.i = uint16(nnnn[0])<<8 | uint16(nnnn[1])
 	c.pc += 4
+	return nil
+}
+
+func (c *Cpu) process0xFN01(n byte) {
+	c.planes = n & 0x3
+	c.display.(PlaneDisplay).SelectPlanes(c.planes)
+	c.pc += 2
+}
+
+func (c *Cpu) process0xF002() error {
+	pattern := [patternSize]byte{}
+	if err := c.checkMemory(c.i, len(pattern)); err != nil {
+		return err
+	}
+
+	c.memory.Load(pattern[:], c.i)
+	c.memoryRead(c.i, pattern[:])
+
+	c.pattern = pattern
+	if sound, ok := c.sound.(PatternSound); ok {
+		sound.SetPattern(c.pattern)
+	}
+	c.pc += 2
+	return nil
 }
 
 func (c *Cpu) process0xFX07(x byte) {
@@ -441,10 +1181,10 @@ func (c *Cpu) process0xFX07(x byte) {
 }
 
 func (c *Cpu) process0xFX0A(x byte) {
-	// Wait for the key press
-	key := c.keyboard.KeyDown()
-	for key == Key(0xFF) {
-		key = c.keyboard.KeyDown()
+	// Wait for the key press, repeating the instruction while none key is down
+	key := c.keyDown()
+	if key == Key(0xFF) {
+		return
 	}
 
 	c.register[x] = byte(key)
@@ -452,12 +1192,12 @@ func (c *Cpu) process0xFX0A(x byte) {
 }
 
 func (c *Cpu) process0xFX15(x byte) {
-	c.dt = c.register[x]
+	c.setTimer(TimerDelay, c.register[x])
 	c.pc += 2
 }
 
 func (c *Cpu) process0xFX18(x byte) {
-	c.st = c.register[x]
+	c.setTimer(TimerSound, c.register[x])
 	c.pc += 2
 }
 
@@ -477,17 +1217,259 @@ func (c *Cpu) process0xFX29(x byte) {
 	c.pc += 2
 }
 
-func (c *Cpu) process0xFX33(x byte) {
+func (c *Cpu) process0xFX30(x byte) {
+	c.i = c.memory.(LargeFontMemory).LoadBigChar(c.register[x])
+	c.pc += 2
+}
+
+func (c *Cpu) process0xFX33(x byte) error {
+	if err := c.checkMemory(c.i, 3); err != nil {
+		return err
+	}
+
 	c.memory.SaveBCD(c.register[x], c.i)
+	if c.hooks.MemoryWrite != nil {
+		vx := c.register[x]
+		c.hooks.MemoryWrite(c.i, []byte{vx / 100, vx / 10 % 10, vx % 10})
+	}
 	c.pc += 2
+	return nil
 }
 
-func (c *Cpu) process0xFX55(x byte) {
+func (c *Cpu) process0xFX3A(x byte) {
+	c.pitch = c.register[x]
+	if sound, ok := c.sound.(PatternSound); ok {
+		sound.SetPitch(c.pitch)
+	}
+	c.pc += 2
+}
+
+func (c *Cpu) process0xFX55(x byte) error {
+	if err := c.checkMemory(c.i, int(x)+1); err != nil {
+		return err
+	}
+
 	c.memory.Save(c.register[0:x+1], c.i)
+	c.memoryWritten(c.i, c.register[0:x+1])
+	if c.quirks.LoadStoreIncrementsI {
+		c.i += uint16(x) + 1
+	}
 	c.pc += 2
+	return nil
 }
 
-func (c *Cpu) process0xFX65(x byte) {
+func (c *Cpu) process0xFX65(x byte) error {
+	if err := c.checkMemory(c.i, int(x)+1); err != nil {
+		return err
+	}
+
 	c.memory.Load(c.register[0:x+1], c.i)
+	c.memoryRead(c.i, c.register[0:x+1])
+	if c.quirks.LoadStoreIncrementsI {
+		c.i += uint16(x) + 1
+	}
+	c.pc += 2
+	return nil
+}
+
+func (c *Cpu) process0xFX75(x byte) {
+	copy(c.flags[0:x+1], c.register[0:x+1])
+	c.pc += 2
+}
+
+func (c *Cpu) process0xFX85(x byte) {
+	copy(c.register[0:x+1], c.flags[0:x+1])
 	c.pc += 2
 }
+
+// supports returns true when the instruction set of Cpu includes the instructions of set
+func (c *Cpu) supports(set InstructionSet) bool {
+	return c.set == InstructionsAll || c.set >= set
+}
+
+// hasPlanes returns true when the display supports the bit planes of XO-CHIP and they are accepted
+func (c *Cpu) hasPlanes() bool {
+	_, ok := c.display.(PlaneDisplay)
+	return ok && c.supports(InstructionsXOCHIP)
+}
+
+// hasLargeFont returns true when the memory holds the big font of SUPER-CHIP and it is accepted
+func (c *Cpu) hasLargeFont() bool {
+	_, ok := c.memory.(LargeFontMemory)
+	return ok && c.supports(InstructionsSCHIP)
+}
+
+// isHiRes returns true when the display supports the instructions of SUPER-CHIP and they are accepted
+func (c *Cpu) isHiRes() bool {
+	_, ok := c.display.(HiResDisplay)
+	return ok && c.supports(InstructionsSCHIP)
+}
+
+// screenSize returns the width and height of the display on current resolution
+func (c *Cpu) screenSize() (int, int) {
+	if display, ok := c.display.(HiResDisplay); ok && display.HighResolution() {
+		return hiResScreenWidth, hiResScreenHeight
+	}
+
+	return screenWidth, screenHeight
+}
+
+// skip jumps over the next instruction, that takes 4 bytes when it is F000 NNNN of XO-CHIP
+func (c *Cpu) skip() {
+	if c.checkMemory(c.pc+2, 2) != nil {
+		c.pc += 4
+		return
+	}
+
+	next := c.memory.LoadInstruction(c.pc + 2)
+	if next[0] == 0xF0 && next[1] == 0x00 && c.supports(InstructionsXOCHIP) {
+		c.pc += 6
+		return
+	}
+	c.pc += 4
+}
+
+// registerRange returns the registers VX..VY, in reverse order when X is greater than Y
+func (c *Cpu) registerRange(x, y byte) []byte {
+	if x <= y {
+		return c.register[x : y+1]
+	}
+
+	register := make([]byte, 0, x-y+1)
+	for idx := int(x); idx >= int(y); idx-- {
+		register = append(register, c.register[idx])
+	}
+
+	return register
+}
+
+// drawingPlanes returns the planes drawn by DXYN, plane 0 means the display does not support planes
+func (c *Cpu) drawingPlanes() []byte {
+	if _, ok := c.display.(PlaneDisplay); !ok {
+		return []byte{0x0}
+	}
+
+	planes := []byte{}
+	for _, plane := range []byte{0x1, 0x2} {
+		if c.planes&plane != 0 {
+			planes = append(planes, plane)
+		}
+	}
+
+	return planes
+}
+
+func (c *Cpu) drawSprite(plane, xDisplay, yDisplay, sprite byte) bool {
+	var collision bool
+	if plane == 0x0 {
+		collision = c.display.Draw(xDisplay, yDisplay, sprite)
+	} else {
+		collision = c.display.(PlaneDisplay).DrawPlane(plane, xDisplay, yDisplay, sprite)
+	}
+
+	if c.hooks.DisplayDraw != nil {
+		c.hooks.DisplayDraw(plane, xDisplay, yDisplay, sprite, collision)
+	}
+
+	return collision
+}
+
+func (c *Cpu) keyDown() Key {
+	key := c.keyboard.KeyDown()
+	if c.hooks.KeyPoll != nil {
+		c.hooks.KeyPoll(key)
+	}
+
+	return key
+}
+
+// setTimer sets the value of timer, calling the hook when it changes
+// The PatternSound plays while the sound timer is active, so it is told when the timer starts and stops
+func (c *Cpu) setTimer(timer Timer, value byte) {
+	current := &c.dt
+	if timer == TimerSound {
+		current = &c.st
+	}
+
+	if *current == value {
+		return
+	}
+
+	playing := *current > 0
+	*current = value
+	if c.hooks.TimerChange != nil {
+		c.hooks.TimerChange(timer, value)
+	}
+
+	if sound, ok := c.sound.(PatternSound); ok && timer == TimerSound && playing != (value > 0) {
+		sound.SetPlaying(value > 0)
+	}
+}
+
+// flush flushes the display and calls the hook with the frames processed
+func (c *Cpu) flush() {
+	c.display.Flush()
+	if c.hooks.DisplayFlush != nil {
+		c.hooks.DisplayFlush(c.frames)
+	}
+}
+
+func (c *Cpu) memoryRead(addr uint16, data []byte) {
+	if c.hooks.MemoryRead != nil {
+		c.hooks.MemoryRead(addr, data)
+	}
+}
+
+func (c *Cpu) memoryWritten(addr uint16, data []byte) {
+	if c.hooks.MemoryWrite != nil {
+		c.hooks.MemoryWrite(addr, data)
+	}
+}
+
+// validateState returns ErrOutOfRange when the registers do not fit the stack, the planes or the memory
+func (c *Cpu) validateState(pc, i uint16, sp byte, stack Stack, planes byte) error {
+	if err := c.validateAddress("pc", pc, 2); err != nil {
+		return err
+	}
+	if err := c.validateAddress("i", i, 1); err != nil {
+		return err
+	}
+	if int(sp) > len(stack) {
+		return fmt.Errorf("%w: sp %d beyond stack of %d levels", ErrOutOfRange, sp, len(stack))
+	}
+	for _, addr := range stack[:sp] {
+		if err := c.validateAddress("stack", addr, 2); err != nil {
+			return err
+		}
+	}
+	if planes > 0x3 {
+		return fmt.Errorf("%w: planes 0x%X", ErrOutOfRange, planes)
+	}
+
+	return nil
+}
+
+// validateAddress returns ErrOutOfRange when size bytes from addr of register name are beyond the memory
+func (c *Cpu) validateAddress(name string, addr uint16, size int) error {
+	memory, ok := c.memory.(interface{ Size() int })
+	if !ok || int(addr)+size <= memory.Size() {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s 0x%04X beyond memory of %d bytes", ErrOutOfRange, name, addr, memory.Size())
+}
+
+func (c *Cpu) unknownOpcode(instr Instruction) error {
+	return &ErrUnknownOpcode{PC: c.pc, Opcode: uint16(instr[0])<<8 | uint16(instr[1])}
+}
+
+// checkMemory returns ErrMemoryOutOfBounds when size bytes from addr are beyond the memory
+// The check is only done when memory reports its size
+func (c *Cpu) checkMemory(addr uint16, size int) error {
+	memory, ok := c.memory.(interface{ Size() int })
+	if !ok || int(addr)+size <= memory.Size() {
+		return nil
+	}
+
+	return &ErrMemoryOutOfBounds{PC: c.pc, Address: int(addr), Size: size}
+}
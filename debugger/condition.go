@@ -0,0 +1,34 @@
+package debugger
+
+import (
+	"fmt"
+
+	chip8 "github.com/MarceloMPJR/go-chip-8"
+)
+
+// Condition decides if a breakpoint stops the execution
+type Condition func(state chip8.State) bool
+
+// Compare returns a Condition that compares register VX with value, op is one of == != < <= > >=
+func Compare(x byte, op string, value byte) (Condition, error) {
+	if int(x) >= len(chip8.Register{}) {
+		return nil, fmt.Errorf("invalid register V%X", x)
+	}
+
+	switch op {
+	case "==":
+		return func(s chip8.State) bool { return s.Register[x] == value }, nil
+	case "!=":
+		return func(s chip8.State) bool { return s.Register[x] != value }, nil
+	case "<":
+		return func(s chip8.State) bool { return s.Register[x] < value }, nil
+	case "<=":
+		return func(s chip8.State) bool { return s.Register[x] <= value }, nil
+	case ">":
+		return func(s chip8.State) bool { return s.Register[x] > value }, nil
+	case ">=":
+		return func(s chip8.State) bool { return s.Register[x] >= value }, nil
+	}
+
+	return nil, fmt.Errorf("invalid operator %q", op)
+}
@@ -0,0 +1,223 @@
+package debugger
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const consoleHelp = `commands:
+  break ADDR [if VX OP VALUE]    add breakpoint, OP is one of == != < <= > >=
+  delete ADDR                    remove breakpoint
+  watch ADDR [SIZE] [r|w|rw]     add watchpoint, by default 1 byte read/write
+  unwatch ADDR                   remove watchpoint
+  step | next | finish           step into, step over and step out
+  continue                       run until breakpoint, watchpoint or halt
+  regs                           show registers
+  stack                          show call stack
+  mem ADDR [SIZE]                show memory, by default 16 bytes
+  quit
+`
+
+// Console reads commands from in and writes the results on out, until quit, EOF or ctx is done
+func (d *Debugger) Console(ctx context.Context, in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(out, "(chip8) ")
+		if !scanner.Scan() {
+			return scanner.Err()
+		}
+
+		args := strings.Fields(scanner.Text())
+		if len(args) == 0 {
+			continue
+		}
+		if args[0] == "quit" || args[0] == "q" {
+			return nil
+		}
+
+		if err := d.command(ctx, args, out); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			fmt.Fprintf(out, "error: %s\n", err.Error())
+		}
+	}
+}
+
+func (d *Debugger) command(ctx context.Context, args []string, out io.Writer) error {
+	switch args[0] {
+	case "break", "b":
+		return d.commandBreak(args[1:])
+	case "delete", "d":
+		address, err := parseArg(args, 1, 0xFFFF)
+		if err != nil {
+			return err
+		}
+		d.RemoveBreakpoint(uint16(address))
+	case "watch", "w":
+		return d.commandWatch(args[1:])
+	case "unwatch":
+		address, err := parseArg(args, 1, 0xFFFF)
+		if err != nil {
+			return err
+		}
+		d.RemoveWatchpoint(uint16(address))
+	case "step", "s":
+		return printStop(out)(d.Step())
+	case "next", "n":
+		return printStop(out)(d.StepOver())
+	case "finish", "f":
+		return printStop(out)(d.StepOut())
+	case "continue", "c":
+		return printStop(out)(d.Continue(ctx))
+	case "regs", "r":
+		d.printRegisters(out)
+	case "stack", "bt":
+		for level, frame := range d.CallStack() {
+			fmt.Fprintf(out, "#%d 0x%03X called from 0x%03X, returns to 0x%03X\n", level, frame.Entry, frame.Caller, frame.Return)
+		}
+	case "mem", "x":
+		address, err := parseArg(args, 1, 0xFFFF)
+		if err != nil {
+			return err
+		}
+		size := uint64(16)
+		if len(args) > 2 {
+			if size, err = parseArg(args, 2, 0xFFFF); err != nil {
+				return err
+			}
+		}
+		d.printMemory(out, uint16(address), int(size))
+	case "help", "h":
+		fmt.Fprint(out, consoleHelp)
+	default:
+		return fmt.Errorf("unknown command %q, try help", args[0])
+	}
+
+	return nil
+}
+
+// commandBreak parses "ADDR [if VX OP VALUE]"
+func (d *Debugger) commandBreak(args []string) error {
+	if len(args) != 1 && len(args) != 5 {
+		return fmt.Errorf("usage: break ADDR [if VX OP VALUE]")
+	}
+
+	address, err := parseArg(args, 0, 0xFFFF)
+	if err != nil {
+		return err
+	}
+
+	var condition Condition
+	if len(args) == 5 {
+		if args[1] != "if" || len(args[2]) != 2 || (args[2][0] != 'V' && args[2][0] != 'v') {
+			return fmt.Errorf("usage: break ADDR [if VX OP VALUE]")
+		}
+
+		x, err := strconv.ParseUint(args[2][1:], 16, 4)
+		if err != nil {
+			return fmt.Errorf("invalid register %s", args[2])
+		}
+
+		value, err := parseArg(args, 4, 0xFF)
+		if err != nil {
+			return err
+		}
+
+		if condition, err = Compare(byte(x), args[3], byte(value)); err != nil {
+			return err
+		}
+	}
+
+	d.AddBreakpoint(uint16(address), condition)
+	return nil
+}
+
+// commandWatch parses "ADDR [SIZE] [r|w|rw]"
+func (d *Debugger) commandWatch(args []string) error {
+	access := ReadWrite
+	if len(args) > 1 {
+		switch args[len(args)-1] {
+		case "r":
+			access, args = Read, args[:len(args)-1]
+		case "w":
+			access, args = Write, args[:len(args)-1]
+		case "rw":
+			args = args[:len(args)-1]
+		}
+	}
+
+	address, err := parseArg(args, 0, 0xFFFF)
+	if err != nil {
+		return err
+	}
+
+	size := uint64(1)
+	if len(args) > 1 {
+		if size, err = parseArg(args, 1, 0xFFFF); err != nil {
+			return err
+		}
+	}
+
+	d.AddWatchpoint(Watchpoint{Address: uint16(address), Size: int(size), Access: access})
+	return nil
+}
+
+func (d *Debugger) printRegisters(out io.Writer) {
+	state := d.cpu.State()
+
+	fmt.Fprintf(out, "pc=0x%03X i=0x%03X sp=%d dt=%d st=%d\n", state.PC, state.I, state.SP, state.DT, state.ST)
+	for x, value := range state.Register {
+		separator := " "
+		if x%8 == 7 {
+			separator = "\n"
+		}
+		fmt.Fprintf(out, "V%X=%02X%s", x, value, separator)
+	}
+}
+
+func (d *Debugger) printMemory(out io.Writer, address uint16, size int) {
+	data := d.Memory(address, size)
+	for line := 0; line < len(data); line += 16 {
+		end := line + 16
+		if end > len(data) {
+			end = len(data)
+		}
+		fmt.Fprintf(out, "0x%03X: % X\n", int(address)+line, data[line:end])
+	}
+}
+
+// printStop returns a function that writes the stop on out
+func printStop(out io.Writer) func(Stop, error) error {
+	return func(stop Stop, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if stop.Reason == ReasonWatchpoint {
+			fmt.Fprintf(out, "%s %s 0x%03X, pc=0x%03X\n", stop.Reason, stop.Access, stop.Address, stop.PC)
+			return nil
+		}
+
+		fmt.Fprintf(out, "%s, pc=0x%03X\n", stop.Reason, stop.PC)
+		return nil
+	}
+}
+
+// parseArg parses the number args[idx], that may be decimal or prefixed by 0x
+func parseArg(args []string, idx int, max uint64) (uint64, error) {
+	if idx >= len(args) {
+		return 0, fmt.Errorf("missing argument")
+	}
+
+	value, err := strconv.ParseUint(args[idx], 0, 64)
+	if err != nil || value > max {
+		return 0, fmt.Errorf("invalid number %s", args[idx])
+	}
+
+	return value, nil
+}
@@ -0,0 +1,248 @@
+package debugger
+
+import (
+	"context"
+	"errors"
+
+	chip8 "github.com/MarceloMPJR/go-chip-8"
+)
+
+// ErrNoCaller is returned by StepOut when there is no subroutine to return from
+var ErrNoCaller = errors.New("no subroutine to step out")
+
+// Reason is why the debugger stopped the execution
+type Reason int
+
+const (
+	// ReasonStep means the step requested was done
+	ReasonStep Reason = iota
+	// ReasonBreakpoint means the next instruction is on a breakpoint
+	ReasonBreakpoint
+	// ReasonWatchpoint means the last instruction accessed a watched address
+	ReasonWatchpoint
+	// ReasonHalted means Cpu is halted
+	ReasonHalted
+)
+
+func (r Reason) String() string {
+	switch r {
+	case ReasonStep:
+		return "step"
+	case ReasonBreakpoint:
+		return "breakpoint"
+	case ReasonWatchpoint:
+		return "watchpoint"
+	case ReasonHalted:
+		return "halted"
+	}
+
+	return "unknown"
+}
+
+// Stop describes where and why the execution stopped
+type Stop struct {
+	Reason Reason
+
+	// PC is the address of the next instruction
+	PC uint16
+
+	// Watchpoint, Access and Address describe the access that stopped on a watchpoint
+	// Address is the first watched address accessed
+	Watchpoint Watchpoint
+	Access     Access
+	Address    uint16
+}
+
+// Frame is a level of call stack
+type Frame struct {
+	// Caller is the address of instruction 2NNN
+	Caller uint16
+
+	// Entry is the address of subroutine called
+	Entry uint16
+
+	// Return is the address where the subroutine returns to
+	Return uint16
+}
+
+// Debugger runs Cpu instruction by instruction, stopping on breakpoints and watchpoints
+// Cpu must not be started while it is debugged
+type Debugger struct {
+	cpu         *chip8.Cpu
+	memory      chip8.Memory
+	breakpoints map[uint16]Condition
+	watchpoints []Watchpoint
+}
+
+type ConfigDebugger struct {
+	// Cpu debugged and its memory
+	Cpu    *chip8.Cpu
+	Memory chip8.Memory
+}
+
+// NewDebugger is a function that receive a config as param and return a pointer to Debugger
+func NewDebugger(config *ConfigDebugger) *Debugger {
+	return &Debugger{
+		cpu:         config.Cpu,
+		memory:      config.Memory,
+		breakpoints: map[uint16]Condition{},
+	}
+}
+
+// AddBreakpoint stops the execution before the instruction on pc
+// When condition is not nil the execution stops only when it is true
+func (d *Debugger) AddBreakpoint(pc uint16, condition Condition) {
+	d.breakpoints[pc] = condition
+}
+
+// RemoveBreakpoint removes the breakpoint on pc
+func (d *Debugger) RemoveBreakpoint(pc uint16) {
+	delete(d.breakpoints, pc)
+}
+
+// AddWatchpoint stops the execution after an instruction accesses the memory watched
+func (d *Debugger) AddWatchpoint(watchpoint Watchpoint) {
+	d.watchpoints = append(d.watchpoints, watchpoint)
+}
+
+// RemoveWatchpoint removes the watchpoints starting on address
+func (d *Debugger) RemoveWatchpoint(address uint16) {
+	watchpoints := d.watchpoints[:0]
+	for _, watchpoint := range d.watchpoints {
+		if watchpoint.Address != address {
+			watchpoints = append(watchpoints, watchpoint)
+		}
+	}
+	d.watchpoints = watchpoints
+}
+
+// Step processes one instruction
+func (d *Debugger) Step() (Stop, error) {
+	return d.run(context.Background(), func(chip8.State) bool { return true })
+}
+
+// StepOver processes one instruction, when it is a call (2NNN) the execution stops after the subroutine returns
+func (d *Debugger) StepOver() (Stop, error) {
+	state := d.cpu.State()
+	instr, ok := d.instruction(state.PC)
+	if !ok || instr[0]>>4 != 0x2 {
+		return d.Step()
+	}
+
+	return d.run(context.Background(), func(s chip8.State) bool { return s.SP <= state.SP })
+}
+
+// StepOut processes instructions until the current subroutine returns (00EE)
+func (d *Debugger) StepOut() (Stop, error) {
+	state := d.cpu.State()
+	if state.SP == 0 {
+		return Stop{PC: state.PC}, ErrNoCaller
+	}
+
+	return d.run(context.Background(), func(s chip8.State) bool { return s.SP < state.SP })
+}
+
+// Continue processes instructions until a breakpoint or watchpoint is hit, Cpu halts or ctx is done
+func (d *Debugger) Continue(ctx context.Context) (Stop, error) {
+	return d.run(ctx, func(chip8.State) bool { return false })
+}
+
+// CallStack returns the subroutines called, the innermost first
+func (d *Debugger) CallStack() []Frame {
+	state := d.cpu.State()
+
+	frames := make([]Frame, 0, state.SP)
+	for level := int(state.SP) - 1; level >= 0 && level < len(state.Stack); level-- {
+		frame := Frame{Caller: state.Stack[level] - 2, Return: state.Stack[level]}
+		if instr, ok := d.instruction(frame.Caller); ok {
+			frame.Entry = uint16(instr[0]&0x0F)<<8 | uint16(instr[1])
+		}
+		frames = append(frames, frame)
+	}
+
+	return frames
+}
+
+// Memory returns size bytes of memory starting on address
+func (d *Debugger) Memory(address uint16, size int) []byte {
+	if memory, ok := d.memory.(interface{ Size() int }); ok && int(address)+size > memory.Size() {
+		size = memory.Size() - int(address)
+	}
+	if size <= 0 {
+		return []byte{}
+	}
+
+	data := make([]byte, size)
+	d.memory.Load(data, address)
+	return data
+}
+
+// run processes instructions until done returns true after an instruction, stopping earlier on
+// breakpoints, watchpoints and halt. The breakpoint of the first instruction is ignored
+func (d *Debugger) run(ctx context.Context, done func(state chip8.State) bool) (Stop, error) {
+	for first := true; ; first = false {
+		select {
+		case <-ctx.Done():
+			return Stop{PC: d.cpu.State().PC}, ctx.Err()
+		default:
+		}
+
+		state := d.cpu.State()
+		if !first && d.isBreakpoint(state) {
+			return Stop{Reason: ReasonBreakpoint, PC: state.PC}, nil
+		}
+
+		stop, watched := d.watched(state)
+
+		if err := d.cpu.Step(); err != nil {
+			return Stop{Reason: ReasonHalted, PC: d.cpu.State().PC}, err
+		}
+
+		after := d.cpu.State()
+		switch {
+		case watched:
+			stop.PC = after.PC
+			return stop, nil
+		case after.Halted:
+			return Stop{Reason: ReasonHalted, PC: after.PC}, nil
+		case done(after):
+			return Stop{Reason: ReasonStep, PC: after.PC}, nil
+		}
+	}
+}
+
+func (d *Debugger) isBreakpoint(state chip8.State) bool {
+	condition, ok := d.breakpoints[state.PC]
+	return ok && (condition == nil || condition(state))
+}
+
+// watched returns the stop of the first watchpoint accessed by the instruction on PC
+func (d *Debugger) watched(state chip8.State) (Stop, bool) {
+	if len(d.watchpoints) == 0 {
+		return Stop{}, false
+	}
+
+	instr, ok := d.instruction(state.PC)
+	if !ok {
+		return Stop{}, false
+	}
+
+	for _, access := range accesses(state, instr) {
+		for _, watchpoint := range d.watchpoints {
+			if address, ok := watchpoint.hit(access); ok {
+				return Stop{Reason: ReasonWatchpoint, Watchpoint: watchpoint, Access: access.kind, Address: address}, true
+			}
+		}
+	}
+
+	return Stop{}, false
+}
+
+// instruction returns the instruction on pc, false when it is beyond the memory
+func (d *Debugger) instruction(pc uint16) (chip8.Instruction, bool) {
+	if memory, ok := d.memory.(interface{ Size() int }); ok && int(pc)+2 > memory.Size() {
+		return nil, false
+	}
+
+	return d.memory.LoadInstruction(pc), true
+}
@@ -0,0 +1,108 @@
+package debugger
+
+import (
+	chip8 "github.com/MarceloMPJR/go-chip-8"
+)
+
+// Access is the kind of memory access watched
+type Access byte
+
+const (
+	Read Access = 1 << iota
+	Write
+
+	ReadWrite = Read | Write
+)
+
+func (a Access) String() string {
+	switch a {
+	case Read:
+		return "read"
+	case Write:
+		return "write"
+	case ReadWrite:
+		return "read/write"
+	}
+
+	return "none"
+}
+
+// Watchpoint watches Size bytes of memory starting on Address
+type Watchpoint struct {
+	Address uint16
+	Size    int
+	Access  Access
+}
+
+// memoryAccess is a range of memory accessed by an instruction
+type memoryAccess struct {
+	address uint16
+	size    int
+	kind    Access
+}
+
+// hit returns the first address of watchpoint accessed
+func (w Watchpoint) hit(access memoryAccess) (uint16, bool) {
+	if w.Access&access.kind == 0 {
+		return 0, false
+	}
+
+	start, end := int(w.Address), int(w.Address)+w.Size
+	if int(access.address) > start {
+		start = int(access.address)
+	}
+	if int(access.address)+access.size < end {
+		end = int(access.address) + access.size
+	}
+
+	return uint16(start), start < end
+}
+
+// accesses returns the memory accessed by instr, beyond the fetch of instruction itself
+// DXY0 is taken as a sprite of 16x16, as it is drawn on SUPER-CHIP
+func accesses(state chip8.State, instr chip8.Instruction) []memoryAccess {
+	x, y, n, nn := instr[0]&0x0F, instr[1]>>4, instr[1]&0x0F, instr[1]
+
+	switch {
+	case instr[0]>>4 == 0x5 && n == 0x2:
+		return []memoryAccess{{state.I, registerCount(x, y), Write}}
+	case instr[0]>>4 == 0x5 && n == 0x3:
+		return []memoryAccess{{state.I, registerCount(x, y), Read}}
+	case instr[0]>>4 == 0xD:
+		rows := int(n)
+		if n == 0 {
+			rows = 32
+		}
+		return []memoryAccess{{state.I, rows * planeCount(state.Planes), Read}}
+	case instr[0] == 0xF0 && nn == 0x02:
+		return []memoryAccess{{state.I, 16, Read}}
+	case instr[0]>>4 == 0xF && nn == 0x33:
+		return []memoryAccess{{state.I, 3, Write}}
+	case instr[0]>>4 == 0xF && nn == 0x55:
+		return []memoryAccess{{state.I, int(x) + 1, Write}}
+	case instr[0]>>4 == 0xF && nn == 0x65:
+		return []memoryAccess{{state.I, int(x) + 1, Read}}
+	}
+
+	return nil
+}
+
+func registerCount(x, y byte) int {
+	if x > y {
+		return int(x-y) + 1
+	}
+
+	return int(y-x) + 1
+}
+
+// planeCount returns how many planes are drawn, a display without planes draws as one plane
+func planeCount(planes byte) int {
+	count := 0
+	for _, plane := range []byte{0x1, 0x2} {
+		if planes&plane != 0 {
+			count++
+		}
+	}
+
+	return count
+}
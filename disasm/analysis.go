@@ -0,0 +1,123 @@
+package disasm
+
+import (
+	"strings"
+
+	chip8 "github.com/MarceloMPJR/go-chip-8"
+)
+
+// analysis finds the code of ROM following the flow of program from its first instruction,
+// all bytes that are not reached are taken as data
+type analysis struct {
+	rom    []byte
+	origin uint16
+	code   map[uint16]int
+	labels map[uint16]bool
+}
+
+func analyze(rom []byte, origin uint16) *analysis {
+	a := &analysis{rom: rom, origin: origin, code: map[uint16]int{}, labels: map[uint16]bool{}}
+
+	queue := []uint16{origin}
+	for len(queue) > 0 {
+		addr := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+
+		for a.isCode(addr) {
+			if _, ok := a.code[addr]; ok {
+				break
+			}
+
+			size := a.size(addr)
+			a.code[addr] = size
+
+			op := a.word(addr)
+			nnn := op & 0x0FFF
+			next := addr + uint16(size)
+			flows := true
+
+			switch {
+			case op == 0x00EE, op == 0x00FD:
+				flows = false
+			case op>>12 == 0x1, op>>12 == 0xB:
+				a.label(nnn)
+				queue = append(queue, nnn)
+				flows = false
+			case op>>12 == 0x2:
+				a.label(nnn)
+				queue = append(queue, nnn)
+			case op>>12 == 0xA:
+				a.label(nnn)
+			case op == 0xF000:
+				a.label(a.word(addr + 2))
+			case isSkip(op):
+				queue = append(queue, next+uint16(a.size(next)))
+			}
+
+			if !flows {
+				break
+			}
+			addr = next
+		}
+	}
+
+	return a
+}
+
+// isCode returns true when there is a known instruction on addr
+func (a *analysis) isCode(addr uint16) bool {
+	if !a.contains(addr, 2) {
+		return false
+	}
+
+	op := a.word(addr)
+	if op == 0xF000 && !a.contains(addr, 4) {
+		return false
+	}
+
+	text := chip8.Instruction{byte(op >> 8), byte(op)}.String()
+	return !strings.HasPrefix(text, "DW ") && !strings.HasPrefix(text, "SYS ")
+}
+
+// size returns the size of instruction on addr, that is 4 bytes to F000 NNNN
+func (a *analysis) size(addr uint16) int {
+	if a.contains(addr, 4) && a.word(addr) == 0xF000 {
+		return 4
+	}
+
+	return 2
+}
+
+// label marks addr as target of instruction, when it is inside the ROM
+func (a *analysis) label(addr uint16) {
+	if a.contains(addr, 1) {
+		a.labels[addr] = true
+	}
+}
+
+func (a *analysis) contains(addr uint16, size int) bool {
+	return addr >= a.origin && int(addr-a.origin)+size <= len(a.rom)
+}
+
+func (a *analysis) word(addr uint16) uint16 {
+	if !a.contains(addr, 2) {
+		return 0
+	}
+
+	idx := addr - a.origin
+	return uint16(a.rom[idx])<<8 | uint16(a.rom[idx+1])
+}
+
+// isSkip returns true for the instructions that may skip the next one
+func isSkip(op uint16) bool {
+	switch op >> 12 {
+	case 0x3, 0x4:
+		return true
+	case 0x5, 0x9:
+		return op&0xF == 0x0
+	case 0xE:
+		return op&0xFF == 0x9E || op&0xFF == 0xA1
+	}
+
+	return false
+}
@@ -0,0 +1,184 @@
+package disasm
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	chip8 "github.com/MarceloMPJR/go-chip-8"
+)
+
+const defaultOrigin = 0x200
+const dataPerLine = 8
+const commentColumn = 24
+
+// Syntax of assembly written by Disassembler
+type Syntax int
+
+const (
+	// Cowgod is the syntax of Cowgod's Chip-8 Technical Reference, e.g. "LD V3, 0x1F"
+	Cowgod Syntax = iota
+	// Octo is the syntax of Octo assembler, e.g. "v3 := 0x1F"
+	Octo
+)
+
+// Line is an instruction or a sequence of data bytes of ROM
+type Line struct {
+	Address uint16
+	Bytes   []byte
+	Code    bool
+
+	// Label of Address, empty when it has none
+	Label string
+
+	// Text is the instruction or the data on syntax of Disassembler
+	Text string
+}
+
+// Disassembler turns a ROM into assembly
+// The code is found following the flow of program from the first address, other bytes are data
+type Disassembler struct {
+	syntax   Syntax
+	origin   uint16
+	comments bool
+}
+
+type ConfigDisassembler struct {
+	Syntax Syntax
+
+	// Address where ROM is loaded, when zero it is 0x200
+	Origin uint16
+
+	// Comments appends the address and bytes of each line
+	Comments bool
+}
+
+// NewDisassembler is a function that receive a config as param and return a pointer to Disassembler
+func NewDisassembler(config *ConfigDisassembler) *Disassembler {
+	origin := config.Origin
+	if origin == 0 {
+		origin = defaultOrigin
+	}
+
+	return &Disassembler{syntax: config.Syntax, origin: origin, comments: config.Comments}
+}
+
+// Disassemble returns the lines of rom
+func (d *Disassembler) Disassemble(rom []byte) []Line {
+	a := analyze(rom, d.origin)
+
+	lines := []Line{}
+	for addr := d.origin; a.contains(addr, 1); {
+		start := addr
+		if size, ok := a.code[addr]; ok {
+			addr += uint16(size)
+		} else {
+			addr++
+			for a.contains(addr, 1) && addr-start < dataPerLine && a.code[addr] == 0 && !a.labels[addr] {
+				addr++
+			}
+		}
+
+		lines = append(lines, Line{
+			Address: start,
+			Bytes:   rom[start-d.origin : addr-d.origin],
+			Code:    a.code[start] != 0,
+		})
+	}
+
+	// The labels inside of an instruction are dropped, their addresses are written as numbers
+	labels := map[uint16]string{}
+	for idx, line := range lines {
+		switch {
+		case d.syntax == Octo && line.Address == d.origin:
+			lines[idx].Label = "main"
+		case a.labels[line.Address]:
+			lines[idx].Label = fmt.Sprintf("L%03X", line.Address)
+		}
+		labels[line.Address] = lines[idx].Label
+	}
+
+	for idx, line := range lines {
+		lines[idx].Text = d.text(line, labels)
+	}
+
+	return lines
+}
+
+// Write writes the assembly of rom on w
+func (d *Disassembler) Write(w io.Writer, rom []byte) error {
+	buf := bufio.NewWriter(w)
+
+	for idx, line := range d.Disassemble(rom) {
+		if line.Label != "" {
+			if idx > 0 {
+				buf.WriteString("\n")
+			}
+			if d.syntax == Octo {
+				fmt.Fprintf(buf, ": %s\n", line.Label)
+			} else {
+				fmt.Fprintf(buf, "%s:\n", line.Label)
+			}
+		}
+
+		if !d.comments {
+			fmt.Fprintf(buf, "\t%s\n", line.Text)
+			continue
+		}
+
+		comment := ";"
+		if d.syntax == Octo {
+			comment = "#"
+		}
+		fmt.Fprintf(buf, "\t%-*s %s 0x%03X  %X\n", commentColumn, line.Text, comment, line.Address, line.Bytes)
+	}
+
+	return buf.Flush()
+}
+
+func (d *Disassembler) text(line Line, labels map[uint16]string) string {
+	address := func(addr uint16) string { return addressText(labels, addr) }
+
+	if !line.Code {
+		data := make([]string, len(line.Bytes))
+		for idx, b := range line.Bytes {
+			data[idx] = fmt.Sprintf("0x%02X", b)
+		}
+
+		if d.syntax == Octo {
+			return strings.Join(data, " ")
+		}
+		return "DB " + strings.Join(data, ", ")
+	}
+
+	instr := chip8.Instruction(line.Bytes[:2])
+	if d.syntax == Octo {
+		return octo(instr, line.Bytes[2:], labels)
+	}
+
+	nnn := uint16(instr[0]&0x0F)<<8 | uint16(instr[1])
+	switch {
+	case instr[0]>>4 == 0x1:
+		return "JP " + address(nnn)
+	case instr[0]>>4 == 0x2:
+		return "CALL " + address(nnn)
+	case instr[0]>>4 == 0xA:
+		return "LD I, " + address(nnn)
+	case instr[0]>>4 == 0xB:
+		return "JP V0, " + address(nnn)
+	case len(line.Bytes) == 4:
+		return "LD I, LONG " + address(uint16(line.Bytes[2])<<8|uint16(line.Bytes[3]))
+	}
+
+	return instr.String()
+}
+
+// addressText returns the label of addr, or addr as number when it has no label
+func addressText(labels map[uint16]string, addr uint16) string {
+	if label := labels[addr]; label != "" {
+		return label
+	}
+
+	return fmt.Sprintf("0x%03X", addr)
+}
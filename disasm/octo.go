@@ -0,0 +1,115 @@
+package disasm
+
+import (
+	"fmt"
+
+	chip8 "github.com/MarceloMPJR/go-chip-8"
+)
+
+// Octo statements of instructions 8XYN by N
+var octo0x8XY = map[byte]string{
+	0x0: ":=", 0x1: "|=", 0x2: "&=", 0x3: "^=", 0x4: "+=",
+	0x5: "-=", 0x6: ">>=", 0x7: "=-", 0xE: "<<=",
+}
+
+// Octo statements of instructions FXNN by NN
+var octo0xFX = map[byte]string{
+	0x07: "v%x := delay", 0x0A: "v%x := key", 0x15: "delay := v%x", 0x18: "buzzer := v%x",
+	0x1E: "i += v%x", 0x29: "i := hex v%x", 0x30: "i := bighex v%x", 0x33: "bcd v%x",
+	0x3A: "pitch := v%x", 0x55: "save v%x", 0x65: "load v%x", 0x75: "saveflags v%x",
+	0x85: "loadflags v%x",
+}
+
+// octo returns the statement of instr on Octo's syntax, operand is the word that follows F000
+// The skips are written as the "if ... then" that compiles to them
+func octo(instr chip8.Instruction, operand []byte, labels map[uint16]string) string {
+	address := func(addr uint16) string { return addressText(labels, addr) }
+
+	x, y := instr[0]&0x0F, instr[1]>>4
+	n, nn := instr[1]&0x0F, instr[1]
+	nnn := uint16(x)<<8 | uint16(nn)
+
+	switch instr[0] >> 4 {
+	case 0x0:
+		switch {
+		case nnn == 0x0E0:
+			return "clear"
+		case nnn == 0x0EE:
+			return "return"
+		case nnn&0xFF0 == 0x0C0:
+			return fmt.Sprintf("scroll-down %d", n)
+		case nnn&0xFF0 == 0x0D0:
+			return fmt.Sprintf("scroll-up %d", n)
+		case nnn == 0x0FB:
+			return "scroll-right"
+		case nnn == 0x0FC:
+			return "scroll-left"
+		case nnn == 0x0FD:
+			return "exit"
+		case nnn == 0x0FE:
+			return "lores"
+		case nnn == 0x0FF:
+			return "hires"
+		}
+	case 0x1:
+		return "jump " + address(nnn)
+	case 0x2:
+		if label := labels[nnn]; label != "" {
+			return label
+		}
+		return fmt.Sprintf(":call 0x%03X", nnn)
+	case 0x3:
+		return fmt.Sprintf("if v%x != 0x%02X then", x, nn)
+	case 0x4:
+		return fmt.Sprintf("if v%x == 0x%02X then", x, nn)
+	case 0x5:
+		switch n {
+		case 0x0:
+			return fmt.Sprintf("if v%x != v%x then", x, y)
+		case 0x2:
+			return fmt.Sprintf("save v%x - v%x", x, y)
+		case 0x3:
+			return fmt.Sprintf("load v%x - v%x", x, y)
+		}
+	case 0x6:
+		return fmt.Sprintf("v%x := 0x%02X", x, nn)
+	case 0x7:
+		return fmt.Sprintf("v%x += 0x%02X", x, nn)
+	case 0x8:
+		if op, ok := octo0x8XY[n]; ok {
+			return fmt.Sprintf("v%x %s v%x", x, op, y)
+		}
+	case 0x9:
+		return fmt.Sprintf("if v%x == v%x then", x, y)
+	case 0xA:
+		return "i := " + address(nnn)
+	case 0xB:
+		return "jump0 " + address(nnn)
+	case 0xC:
+		return fmt.Sprintf("v%x := random 0x%02X", x, nn)
+	case 0xD:
+		return fmt.Sprintf("sprite v%x v%x %d", x, y, n)
+	case 0xE:
+		switch nn {
+		case 0x9E:
+			return fmt.Sprintf("if v%x -key then", x)
+		case 0xA1:
+			return fmt.Sprintf("if v%x key then", x)
+		}
+	case 0xF:
+		switch {
+		case x == 0x0 && nn == 0x00 && len(operand) == 2:
+			return "i := long " + address(uint16(operand[0])<<8|uint16(operand[1]))
+		case nn == 0x01:
+			return fmt.Sprintf("plane %d", x)
+		case x == 0x0 && nn == 0x02:
+			return "audio"
+		}
+
+		if format, ok := octo0xFX[nn]; ok {
+			return fmt.Sprintf(format, x)
+		}
+	}
+
+	return fmt.Sprintf("0x%02X 0x%02X", instr[0], instr[1])
+}
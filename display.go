@@ -1,5 +1,10 @@
 package chip8
 
+const screenWidth = 64
+const screenHeight = 32
+const hiResScreenWidth = 128
+const hiResScreenHeight = 64
+
 type Display interface {
 	/*
 		Clear should clear the display
@@ -17,3 +22,92 @@ type Display interface {
 	*/
 	Flush()
 }
+
+// HiResDisplay is a Display able to run the high resolution mode of SUPER-CHIP
+type HiResDisplay interface {
+	Display
+
+	/*
+		SetHighResolution should switch the display to 128x64 when enabled is true and to 64x32 otherwise
+	*/
+	SetHighResolution(enabled bool)
+
+	/*
+		HighResolution should return true when the display is on 128x64
+	*/
+	HighResolution() bool
+
+	/*
+		ScrollDown should move all pixels n lines down
+	*/
+	ScrollDown(n byte)
+
+	/*
+		ScrollUp should move all pixels n lines up, it is the instruction 00DN of XO-CHIP
+	*/
+	ScrollUp(n byte)
+
+	/*
+		ScrollRight should move all pixels 4 columns right
+	*/
+	ScrollRight()
+
+	/*
+		ScrollLeft should move all pixels 4 columns left
+	*/
+	ScrollLeft()
+}
+
+// PlaneDisplay is a HiResDisplay with the two bit planes of XO-CHIP
+type PlaneDisplay interface {
+	HiResDisplay
+
+	/*
+		SelectPlanes should select the planes (bit mask, 0x1 is the first plane and 0x2 the second plane)
+		changed by Clear, Draw and the scrolls
+	*/
+	SelectPlanes(mask byte)
+
+	/*
+		DrawPlane should draw the byte on position xDisplay and yDisplay only on plane (0x1 or 0x2)
+		If occourres collision returns true
+	*/
+	DrawPlane(plane, xDisplay, yDisplay, sprite byte) bool
+}
+
+// ResettableDisplay is a Display that can restore its power-on state
+type ResettableDisplay interface {
+	Display
+
+	/*
+		Reset should clear all planes, back to low resolution with only plane 1 selected
+	*/
+	Reset()
+}
+
+// Framebuffer is a Display whose pixels can be read by the frontend, also while Cpu runs
+type Framebuffer interface {
+	Display
+
+	/*
+		Width and Height should return the size of screen on current resolution
+	*/
+	Width() int
+	Height() int
+
+	/*
+		Pixel should return the planes set on pixel x, y: bit 0 for plane 1 and bit 1 for plane 2
+		It should return 0 when the pixel is off or out of screen
+	*/
+	Pixel(x, y int) byte
+
+	/*
+		Pixels should copy all pixels, as returned by Pixel, row by row to dst reusing its capacity, and return it
+	*/
+	Pixels(dst []byte) []byte
+
+	/*
+		Version should return a counter incremented on each change of pixels, so unchanged frames can be skipped
+	*/
+	Version() uint64
+}
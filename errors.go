@@ -0,0 +1,51 @@
+package chip8
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrStackOverflow is returned when 2NNN is executed with all levels of stack in use
+var ErrStackOverflow = errors.New("stack overflow")
+
+// ErrStackUnderflow is returned when 00EE is executed with the stack empty
+var ErrStackUnderflow = errors.New("stack underflow")
+
+// ErrOutOfRange is returned by the setters of Cpu when a value does not fit the machine
+var ErrOutOfRange = errors.New("value out of range")
+
+// ErrNotResettable is returned by LoadROM when the memory does not implement ResettableMemory
+var ErrNotResettable = errors.New("memory is not resettable")
+
+// ErrInvalidFramebuffer is returned by Image when the pixels of a Framebuffer do not match its width and height
+var ErrInvalidFramebuffer = errors.New("invalid framebuffer")
+
+// ErrRunning is returned by Run of Machine when another Run is in progress
+var ErrRunning = errors.New("machine is already running")
+
+// ErrRecording is returned by Start of Recorder when a recording is in progress
+var ErrRecording = errors.New("recorder is already recording")
+
+// ErrHalted is returned when an instruction is processed by a halted Cpu
+var ErrHalted = errors.New("cpu is halted")
+
+// ErrUnknownOpcode is returned when the instruction is not supported by Cpu and its devices
+type ErrUnknownOpcode struct {
+	PC     uint16
+	Opcode uint16
+}
+
+func (e *ErrUnknownOpcode) Error() string {
+	return fmt.Sprintf("unknown opcode 0x%04X at 0x%03X", e.Opcode, e.PC)
+}
+
+// ErrMemoryOutOfBounds is returned when the instruction accesses an address beyond the size of memory
+type ErrMemoryOutOfBounds struct {
+	PC      uint16
+	Address int
+	Size    int
+}
+
+func (e *ErrMemoryOutOfBounds) Error() string {
+	return fmt.Sprintf("memory access of %d bytes on 0x%04X out of bounds at 0x%03X", e.Size, e.Address, e.PC)
+}
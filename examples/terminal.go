@@ -7,10 +7,14 @@ package main
 import (
 	"bufio"
 	"bytes"
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
 	"os/exec"
+	"os/signal"
+	"sync"
 
 	chip8 "github.com/MarceloMPJR/go-chip-8"
 )
@@ -24,51 +28,85 @@ func (k *KeyBoardInput) Read(p []byte) (n int, err error) {
 	return 1, nil
 }
 
-// FakeSound implements chip8.Sound
-type FakeSound struct{}
+// ScreenBuffer implements io.Writer, it is safe to be read while the display writes on it
+type ScreenBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (sb *ScreenBuffer) Write(p []byte) (n int, err error) {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+
+	return sb.buf.Write(p)
+}
+
+// Take returns the content written and empties the buffer
+func (sb *ScreenBuffer) Take() string {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
 
-func (fs *FakeSound) Beep() {
+	str := sb.buf.String()
+	sb.buf.Reset()
+	return str
+}
+
+var renderers = map[string]chip8.Renderer{
+	"blocks":    chip8.RendererBlocks,
+	"halfblock": chip8.RendererHalfBlock,
+	"braille":   chip8.RendererBraille,
+	"ascii":     chip8.RendererASCII,
 }
 
 func main() {
 	filepath := flag.String("file", "", "path of CHIP-8 program")
+	renderer := flag.String("renderer", "blocks", "how the screen is painted: blocks, halfblock, braille or ascii")
 	flag.Parse()
 
 	if *filepath == "" {
 		panic("param 'file' is required")
 	}
 
-	f, _ := os.Open(*filepath)
-	buf := bufio.NewReader(f)
+	f, err := os.Open(*filepath)
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
 
-	memory := chip8.NewStandardMemory(&chip8.ConfigMemory{Rom: buf})
+	output := &ScreenBuffer{}
+	config := &chip8.ConfigDisplay{Output: output}
+	var ok bool
+	if config.Renderer, ok = renderers[*renderer]; !ok {
+		panic("unknown renderer " + *renderer)
+	}
 
-	output := &bytes.Buffer{}
-	display := chip8.NewStandardDisplay(&chip8.ConfigDisplay{Output: output})
+	machine := chip8.NewMachine(
+		chip8.WithDisplay(chip8.NewStandardDisplay(config)),
+		chip8.WithKeyboard(chip8.NewStandardKeyboard(&chip8.ConfigKeyboard{Input: &KeyBoardInput{}})),
+	)
 
-	keyboard := chip8.NewStandardKeyboard(&chip8.ConfigKeyboard{Input: &KeyBoardInput{}})
+	if err := machine.Load(bufio.NewReader(f)); err != nil {
+		panic(err)
+	}
 
 	go paintScreen(output)
 
-	cpu := chip8.NewCpu(&chip8.ConfigCpu{
-		Display:  display,
-		Keyboard: keyboard,
-		Sound:    &FakeSound{},
-		Memory:   memory,
-		PC:       0x200,
-	})
+	// Stop the interpreter on Ctrl+C
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
 
-	cpu.Start()
+	if err := machine.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+		panic(err)
+	}
 }
 
-func paintScreen(screenBuffer *bytes.Buffer) {
+func paintScreen(screenBuffer *ScreenBuffer) {
 	for {
-		if len(screenBuffer.Bytes()) > 0 {
+		if screen := screenBuffer.Take(); len(screen) > 0 {
 			// Clean Screen
 			runCmd("clear")
 
-			fmt.Print(screenBuffer.String())
-			screenBuffer.Reset()
+			fmt.Print(screen)
 		}
 	}
 }
@@ -0,0 +1,53 @@
+package chip8
+
+// Timer identifies a timer of Cpu
+type Timer byte
+
+const (
+	TimerDelay Timer = iota
+	TimerSound
+)
+
+func (t Timer) String() string {
+	if t == TimerSound {
+		return "ST"
+	}
+
+	return "DT"
+}
+
+// Hooks are callbacks to observe the events of Cpu, a nil callback is not called and costs nothing
+// They are called holding the lock of Cpu, so they must not call Cpu and should return quickly
+// The slices received are only valid during the call
+type Hooks struct {
+	// BeforeInstruction is called before processing instr, with the state of Cpu
+	BeforeInstruction func(instr Instruction, state State)
+
+	// AfterInstruction is called after processing instr, also when it fails with err
+	AfterInstruction func(instr Instruction, state State, err error)
+
+	// MemoryRead is called with the bytes read from addr by 5XY3, F002, FX65 and by DXYN for each sprite row
+	MemoryRead func(addr uint16, data []byte)
+
+	// MemoryWrite is called with the bytes written on addr by 5XY2, FX33 and FX55
+	MemoryWrite func(addr uint16, data []byte)
+
+	// DisplayDraw is called for each sprite row drawn, plane is 0 when the display does not support planes
+	DisplayDraw func(plane, xDisplay, yDisplay, sprite byte, collision bool)
+
+	// DisplayClear is called when 00E0 clears the display
+	DisplayClear func()
+
+	// DisplayFlush is called after each Flush of display, with the frames processed until then
+	// The emulated time of the flush is frames/60 seconds
+	DisplayFlush func(frames uint64)
+
+	// KeyPoll is called with the key returned by Keyboard to EX9E, EXA1 and FX0A
+	KeyPoll func(key Key)
+
+	// TimerChange is called with the new value of a timer, changed by FX15, FX18 or by the end of a frame
+	TimerChange func(timer Timer, value byte)
+
+	// FrameEnd is called at the end of each frame, after the timers, with the frames processed until then
+	FrameEnd func(frames uint64)
+}
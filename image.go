@@ -0,0 +1,99 @@
+package chip8
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+)
+
+// DefaultPalette has the colors of pixels when off, on plane 1, on plane 2 and on both planes
+var DefaultPalette = color.Palette{
+	color.Gray{Y: 0x00},
+	color.Gray{Y: 0xFF},
+	color.Gray{Y: 0xAA},
+	color.Gray{Y: 0x55},
+}
+
+// ConfigImage sets the colors and size of the images of a Framebuffer
+type ConfigImage struct {
+	// Palette has the colors indexed by pixel: background, plane 1, plane 2 and both planes
+	// With less colors the pixels beyond take the last one, so two colors are background and foreground
+	// When empty it is DefaultPalette
+	Palette color.Palette
+
+	// Scale is the side in pixels of image of each pixel of display, when zero it is 1
+	Scale int
+}
+
+// frameAttempts is how many times a frame is read while the resolution changes during the copy
+const frameAttempts = 3
+
+// Image returns the current frame of framebuffer as an image.Paletted
+// The index of each pixel of image is the pixel of framebuffer, limited to the size of palette
+// It returns ErrInvalidFramebuffer when the pixels of framebuffer do not match its width and height
+func Image(framebuffer Framebuffer, config *ConfigImage) (*image.Paletted, error) {
+	palette := config.Palette
+	if len(palette) == 0 {
+		palette = DefaultPalette
+	}
+
+	scale := config.Scale
+	if scale <= 0 {
+		scale = 1
+	}
+
+	width, height, pixels, err := frame(framebuffer)
+	if err != nil {
+		return nil, err
+	}
+
+	img := image.NewPaletted(image.Rect(0, 0, width*scale, height*scale), palette)
+
+	last := byte(len(palette) - 1)
+	for y := 0; y < height; y++ {
+		row := img.Pix[y*scale*img.Stride : y*scale*img.Stride+width*scale]
+		for x := 0; x < width; x++ {
+			index := pixels[y*width+x]
+			if index > last {
+				index = last
+			}
+
+			for i := 0; i < scale; i++ {
+				row[x*scale+i] = index
+			}
+		}
+
+		// The other lines of scale repeat the first
+		for i := 1; i < scale; i++ {
+			copy(img.Pix[(y*scale+i)*img.Stride:], row)
+		}
+	}
+
+	return img, nil
+}
+
+// Screenshot writes the current frame of framebuffer as PNG
+func Screenshot(w io.Writer, framebuffer Framebuffer, config *ConfigImage) error {
+	img, err := Image(framebuffer, config)
+	if err != nil {
+		return err
+	}
+
+	return png.Encode(w, img)
+}
+
+// frame copies the pixels of framebuffer with its size, trying again when the resolution changes during the copy
+func frame(framebuffer Framebuffer) (int, int, []byte, error) {
+	var pixels []byte
+	for attempt := 0; attempt < frameAttempts; attempt++ {
+		width, height := framebuffer.Width(), framebuffer.Height()
+		pixels = framebuffer.Pixels(pixels)
+		if len(pixels) == width*height {
+			return width, height, pixels, nil
+		}
+	}
+
+	return 0, 0, nil, fmt.Errorf("%w: %d pixels, expected %dx%d", ErrInvalidFramebuffer, len(pixels), framebuffer.Width(), framebuffer.Height())
+}
@@ -8,6 +8,34 @@ type Instruction []byte
 type InstructionType byte
 type InstructionSubType byte
 
+// InstructionSet selects the instructions accepted by Cpu, the others are unknown opcodes
+type InstructionSet int
+
+const (
+	// InstructionsAll accepts every instruction supported by the devices of Cpu
+	InstructionsAll InstructionSet = iota
+	// InstructionsCHIP8 accepts only the instructions of the original CHIP-8
+	InstructionsCHIP8
+	// InstructionsSCHIP accepts the instructions of CHIP-8 and SUPER-CHIP 1.1
+	InstructionsSCHIP
+	// InstructionsXOCHIP accepts the instructions of CHIP-8, SUPER-CHIP and XO-CHIP
+	InstructionsXOCHIP
+)
+
+// Mnemonics of instructions 8XYN by N
+var mnemonics0x8XY = map[byte]string{
+	0x0: "LD", 0x1: "OR", 0x2: "AND", 0x3: "XOR", 0x4: "ADD",
+	0x5: "SUB", 0x6: "SHR", 0x7: "SUBN", 0xE: "SHL",
+}
+
+// Mnemonics of instructions FXNN by NN
+var mnemonics0xFX = map[byte]string{
+	0x07: "LD V%X, DT", 0x0A: "LD V%X, K", 0x15: "LD DT, V%X", 0x18: "LD ST, V%X",
+	0x1E: "ADD I, V%X", 0x29: "LD F, V%X", 0x30: "LD HF, V%X", 0x33: "LD B, V%X",
+	0x3A: "PITCH V%X", 0x55: "LD [I], V%X", 0x65: "LD V%X, [I]", 0x75: "LD R, V%X",
+	0x85: "LD V%X, R",
+}
+
 // GetX returns the parameter X of instruction
 func (instr *Instruction) GetX() (byte, error) {
 	if err := instr.validate(); err != nil {
@@ -98,3 +126,99 @@ func (instr *Instruction) validate() error {
 
 	return nil
 }
+
+// String returns the mnemonic of instruction on Cowgod's syntax, e.g. "LD V3, 0x1F" and "DRW V0, V1, 5"
+// Instructions of SUPER-CHIP and XO-CHIP are included, an unknown instruction is returned as data "DW 0xNNNN"
+func (instr Instruction) String() string {
+	if err := instr.validate(); err != nil {
+		return err.Error()
+	}
+
+	x, y := instr.firstByte()&0x0F, instr.secondByte()>>4
+	n, nn := instr.secondByte()&0x0F, instr.secondByte()
+	nnn := uint16(x)<<8 | uint16(nn)
+
+	switch instr.firstByte() >> 4 {
+	case 0x0:
+		switch {
+		case nnn == 0x0E0:
+			return "CLS"
+		case nnn == 0x0EE:
+			return "RET"
+		case nnn&0xFF0 == 0x0C0:
+			return fmt.Sprintf("SCD %d", n)
+		case nnn&0xFF0 == 0x0D0:
+			return fmt.Sprintf("SCU %d", n)
+		case nnn == 0x0FB:
+			return "SCR"
+		case nnn == 0x0FC:
+			return "SCL"
+		case nnn == 0x0FD:
+			return "EXIT"
+		case nnn == 0x0FE:
+			return "LOW"
+		case nnn == 0x0FF:
+			return "HIGH"
+		}
+		return fmt.Sprintf("SYS 0x%03X", nnn)
+	case 0x1:
+		return fmt.Sprintf("JP 0x%03X", nnn)
+	case 0x2:
+		return fmt.Sprintf("CALL 0x%03X", nnn)
+	case 0x3:
+		return fmt.Sprintf("SE V%X, 0x%02X", x, nn)
+	case 0x4:
+		return fmt.Sprintf("SNE V%X, 0x%02X", x, nn)
+	case 0x5:
+		switch n {
+		case 0x0:
+			return fmt.Sprintf("SE V%X, V%X", x, y)
+		case 0x2:
+			return fmt.Sprintf("SAVE V%X, V%X", x, y)
+		case 0x3:
+			return fmt.Sprintf("LOAD V%X, V%X", x, y)
+		}
+	case 0x6:
+		return fmt.Sprintf("LD V%X, 0x%02X", x, nn)
+	case 0x7:
+		return fmt.Sprintf("ADD V%X, 0x%02X", x, nn)
+	case 0x8:
+		if mnemonic, ok := mnemonics0x8XY[n]; ok {
+			return fmt.Sprintf("%s V%X, V%X", mnemonic, x, y)
+		}
+	case 0x9:
+		if n == 0x0 {
+			return fmt.Sprintf("SNE V%X, V%X", x, y)
+		}
+	case 0xA:
+		return fmt.Sprintf("LD I, 0x%03X", nnn)
+	case 0xB:
+		return fmt.Sprintf("JP V0, 0x%03X", nnn)
+	case 0xC:
+		return fmt.Sprintf("RND V%X, 0x%02X", x, nn)
+	case 0xD:
+		return fmt.Sprintf("DRW V%X, V%X, %d", x, y, n)
+	case 0xE:
+		switch nn {
+		case 0x9E:
+			return fmt.Sprintf("SKP V%X", x)
+		case 0xA1:
+			return fmt.Sprintf("SKNP V%X", x)
+		}
+	case 0xF:
+		switch {
+		case x == 0x0 && nn == 0x00:
+			return "LD I, LONG"
+		case nn == 0x01:
+			return fmt.Sprintf("PLANE %d", x)
+		case x == 0x0 && nn == 0x02:
+			return "AUDIO"
+		}
+
+		if format, ok := mnemonics0xFX[nn]; ok {
+			return fmt.Sprintf(format, x)
+		}
+	}
+
+	return fmt.Sprintf("DW 0x%02X%02X", instr.firstByte(), instr.secondByte())
+}
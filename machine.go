@@ -0,0 +1,291 @@
+package chip8
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+)
+
+// Platform selects the quirks, the instructions and the memory size of a Machine
+type Platform int
+
+const (
+	// PlatformModern has QuirksModern, InstructionsAll and 4 KiB of memory
+	PlatformModern Platform = iota
+	// PlatformVIP has QuirksVIP, InstructionsCHIP8 and 4 KiB of memory
+	PlatformVIP
+	// PlatformSCHIP has QuirksSCHIP, InstructionsSCHIP and 4 KiB of memory
+	PlatformSCHIP
+	// PlatformXOCHIP has QuirksXOCHIP, InstructionsXOCHIP and 64 KiB of memory
+	PlatformXOCHIP
+)
+
+// MachineOption configures a Machine, see NewMachine
+type MachineOption func(config *machineConfig)
+
+type machineConfig struct {
+	cpu        ConfigCpu
+	memorySize int
+	speed      int
+	hasSpeed   bool
+}
+
+// WithPlatform sets the quirks, the instructions and the memory size of platform,
+// WithQuirks given after it overrides the quirks
+func WithPlatform(platform Platform) MachineOption {
+	return func(config *machineConfig) {
+		config.memorySize = memSize
+
+		switch platform {
+		case PlatformVIP:
+			config.cpu.Quirks = QuirksVIP
+			config.cpu.Instructions = InstructionsCHIP8
+		case PlatformSCHIP:
+			config.cpu.Quirks = QuirksSCHIP
+			config.cpu.Instructions = InstructionsSCHIP
+		case PlatformXOCHIP:
+			config.cpu.Quirks = QuirksXOCHIP
+			config.cpu.Instructions = InstructionsXOCHIP
+			config.memorySize = XOChipMemorySize
+		default:
+			config.cpu.Quirks = QuirksModern
+			config.cpu.Instructions = InstructionsAll
+		}
+	}
+}
+
+// WithQuirks sets the behavior of ambiguous instructions
+func WithQuirks(quirks Quirks) MachineOption {
+	return func(config *machineConfig) {
+		config.cpu.Quirks = quirks
+	}
+}
+
+// WithSpeed sets the instructions processed by second, see Cpu.SetSpeed
+func WithSpeed(instructionsPerSecond int) MachineOption {
+	return func(config *machineConfig) {
+		config.speed = instructionsPerSecond
+		config.hasSpeed = true
+	}
+}
+
+// WithDisplay sets the display, when not set the screen is discarded
+func WithDisplay(display Display) MachineOption {
+	return func(config *machineConfig) {
+		config.cpu.Display = display
+	}
+}
+
+// WithKeyboard sets the keyboard, when not set none key is ever down
+func WithKeyboard(keyboard Keyboard) MachineOption {
+	return func(config *machineConfig) {
+		config.cpu.Keyboard = keyboard
+	}
+}
+
+// WithSound sets the sound, when not set it is silent
+func WithSound(sound Sound) MachineOption {
+	return func(config *machineConfig) {
+		config.cpu.Sound = sound
+	}
+}
+
+// WithRandom sets the source of instruction CXNN
+func WithRandom(random Random) MachineOption {
+	return func(config *machineConfig) {
+		config.cpu.Random = random
+	}
+}
+
+// WithClock sets the clock that paces Run
+func WithClock(clock Clock) MachineOption {
+	return func(config *machineConfig) {
+		config.cpu.Clock = clock
+	}
+}
+
+// WithTracer sets the tracer of the instructions processed
+func WithTracer(tracer Tracer) MachineOption {
+	return func(config *machineConfig) {
+		config.cpu.Tracer = tracer
+	}
+}
+
+// WithHooks sets the callbacks of events of Cpu
+func WithHooks(hooks Hooks) MachineOption {
+	return func(config *machineConfig) {
+		config.cpu.Hooks = hooks
+	}
+}
+
+// noKeyboard implements Keyboard without any key down
+type noKeyboard struct{}
+
+func (noKeyboard) KeyDown() Key {
+	return Key(0xFF)
+}
+
+// noSound implements Sound without any output
+type noSound struct{}
+
+func (noSound) Beep() {
+}
+
+// Machine wires a Cpu to a StandardMemory and the devices, owning the lifecycle of the interpreter:
+// Load a ROM, Run it and Pause, Resume, Reset or Stop it from other goroutines
+type Machine struct {
+	mu      sync.Mutex
+	cpu     *Cpu
+	memory  Memory
+	display Display
+
+	// running is true while Run drives cpu, cancel stops that Run
+	running bool
+	cancel  context.CancelFunc
+
+	// stopped is true when Stop was called while not running, so the next Run returns at once
+	stopped bool
+}
+
+// NewMachine returns a pointer to Machine configured by options, with an empty ROM loaded
+// The default is PlatformModern running 480 instructions by second
+func NewMachine(options ...MachineOption) *Machine {
+	config := &machineConfig{memorySize: memSize}
+	for _, option := range options {
+		option(config)
+	}
+
+	if config.cpu.Display == nil {
+		config.cpu.Display = NewStandardDisplay(&ConfigDisplay{Output: io.Discard})
+	}
+	if config.cpu.Keyboard == nil {
+		config.cpu.Keyboard = noKeyboard{}
+	}
+	if config.cpu.Sound == nil {
+		config.cpu.Sound = noSound{}
+	}
+
+	config.cpu.Memory = NewStandardMemory(&ConfigMemory{Rom: bytes.NewReader(nil), Size: config.memorySize})
+	config.cpu.PC = defaultEntry
+
+	cpu := NewCpu(&config.cpu)
+	if config.hasSpeed {
+		cpu.SetSpeed(config.speed)
+	}
+
+	return &Machine{cpu: cpu, memory: config.cpu.Memory, display: config.cpu.Display}
+}
+
+// Cpu returns the Cpu of machine, to inspect it or save its state
+func (m *Machine) Cpu() *Cpu {
+	return m.cpu
+}
+
+// Memory returns the memory of machine, to inspect it or debug it
+func (m *Machine) Memory() Memory {
+	return m.memory
+}
+
+// Display returns the display of machine
+func (m *Machine) Display() Display {
+	return m.display
+}
+
+// Load replaces the ROM and resets the machine, also while it runs
+// A Stop called while the machine was not running is discarded, so the next Run runs the new ROM
+func (m *Machine) Load(rom io.Reader) error {
+	m.clearStop()
+	return m.cpu.LoadROM(rom)
+}
+
+// Reset restores the power-on state of machine, keeping the ROM loaded
+// A Stop called while the machine was not running is discarded, as by Load
+func (m *Machine) Reset() {
+	m.clearStop()
+	m.cpu.Reset()
+}
+
+// Run runs the ROM until the machine is stopped or halted, returning the error that halted it
+// When ctx is done ctx.Err() is returned, while paused Run waits for Resume
+// Only one Run is allowed at a time, another one returns ErrRunning
+func (m *Machine) Run(ctx context.Context) error {
+	m.mu.Lock()
+	if m.running {
+		m.mu.Unlock()
+		return ErrRunning
+	}
+	if m.stopped {
+		m.stopped = false
+		m.mu.Unlock()
+		return nil
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	m.running, m.cancel = true, cancel
+	m.mu.Unlock()
+
+	defer func() {
+		m.mu.Lock()
+		m.running, m.cancel = false, nil
+		m.mu.Unlock()
+
+		cancel()
+	}()
+
+	err := m.cpu.Start(runCtx)
+	if err == context.Canceled && ctx.Err() == nil {
+		// Cancelled by Stop
+		return nil
+	}
+
+	return err
+}
+
+// Pause stops the processing after the current frame, timers included, until Resume
+func (m *Machine) Pause() {
+	m.cpu.Pause()
+}
+
+// Resume continues the processing stopped by Pause
+func (m *Machine) Resume() {
+	m.cpu.Resume()
+}
+
+// Paused returns true when the machine is paused
+func (m *Machine) Paused() bool {
+	return m.cpu.Paused()
+}
+
+// FrameAdvance pauses the machine and processes a single frame
+func (m *Machine) FrameAdvance() error {
+	return m.cpu.FrameAdvance()
+}
+
+// SetSpeed sets the instructions processed by second, zero is unlimited, see Cpu.SetSpeed
+func (m *Machine) SetSpeed(instructionsPerSecond int) {
+	m.cpu.SetSpeed(instructionsPerSecond)
+}
+
+// Stop makes Run return nil after the current frame, the machine can run again keeping its state
+// When the machine is not running, the next Run returns nil at once, so a Stop racing with the start of Run is not lost
+// Load and Reset discard that Stop, as they start the machine again
+func (m *Machine) Stop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.running {
+		m.cancel()
+		return
+	}
+
+	m.stopped = true
+}
+
+// clearStop discards a Stop called while the machine was not running
+func (m *Machine) clearStop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.stopped = false
+}
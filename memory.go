@@ -1,5 +1,7 @@
 package chip8
 
+import "io"
+
 type Memory interface {
 	/*
 		Save should saves the register on the memory starting from I on memory
@@ -31,3 +33,28 @@ type Memory interface {
 	*/
 	LoadSprite(i uint16) byte
 }
+
+// LargeFontMemory is a Memory that holds the 8x10 font of SUPER-CHIP
+type LargeFontMemory interface {
+	Memory
+
+	/*
+		LoadBigChar should return the address of the big char referring to vx
+	*/
+	LoadBigChar(vx byte) uint16
+}
+
+// ResettableMemory is a Memory that can restore its power-on content and replace the ROM loaded
+type ResettableMemory interface {
+	Memory
+
+	/*
+		Reset should clear the memory, loading again the fonts and the ROM
+	*/
+	Reset()
+
+	/*
+		LoadROM should replace the ROM and reset the memory, returning an error when the ROM does not fit
+	*/
+	LoadROM(rom io.Reader) error
+}
@@ -0,0 +1,128 @@
+package octo
+
+// Binary operators of :calc, as Octo they have no precedence and are evaluated from right to left:
+// 1 + 2 * 3 is 1 + (2 * 3) and 2 * 3 + 1 is 2 * (3 + 1)
+var calcBinary = map[string]func(a, b int64) (int64, bool){
+	"+":   func(a, b int64) (int64, bool) { return a + b, true },
+	"-":   func(a, b int64) (int64, bool) { return a - b, true },
+	"*":   func(a, b int64) (int64, bool) { return a * b, true },
+	"/":   func(a, b int64) (int64, bool) { return safeDiv(a, b, false) },
+	"%":   func(a, b int64) (int64, bool) { return safeDiv(a, b, true) },
+	"&":   func(a, b int64) (int64, bool) { return a & b, true },
+	"|":   func(a, b int64) (int64, bool) { return a | b, true },
+	"^":   func(a, b int64) (int64, bool) { return a ^ b, true },
+	"<<":  func(a, b int64) (int64, bool) { return a << uint(b), b >= 0 },
+	">>":  func(a, b int64) (int64, bool) { return a >> uint(b), b >= 0 },
+	"min": func(a, b int64) (int64, bool) { return minInt(a, b), true },
+	"max": func(a, b int64) (int64, bool) { return -minInt(-a, -b), true },
+	"pow": func(a, b int64) (int64, bool) { return pow(a, b) },
+}
+
+// Unary operators of :calc
+var calcUnary = map[string]func(a int64) int64{
+	"-": func(a int64) int64 { return -a },
+	"~": func(a int64) int64 { return ^a },
+	"!": func(a int64) int64 {
+		if a == 0 {
+			return 1
+		}
+		return 0
+	},
+}
+
+// calc evaluates the tokens of expression between braces, the opening brace was consumed
+func (c *compiler) calc() (int64, error) {
+	value, err := c.calcExpression()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := c.expect("}"); err != nil {
+		return 0, err
+	}
+
+	return value, nil
+}
+
+func (c *compiler) calcExpression() (int64, error) {
+	left, err := c.calcTerm()
+	if err != nil {
+		return 0, err
+	}
+
+	op, ok := calcBinary[c.peek().text]
+	if !ok {
+		return left, nil
+	}
+	tok := c.next()
+
+	right, err := c.calcExpression()
+	if err != nil {
+		return 0, err
+	}
+
+	value, ok := op(left, right)
+	if !ok {
+		return 0, errorf(tok, "invalid operands %d %s %d", left, tok.text, right)
+	}
+
+	return value, nil
+}
+
+func (c *compiler) calcTerm() (int64, error) {
+	if c.done() {
+		return 0, errorf(c.last(), "expression expected")
+	}
+
+	tok := c.next()
+	if tok.text == "(" {
+		value, err := c.calcExpression()
+		if err != nil {
+			return 0, err
+		}
+		return value, c.expect(")")
+	}
+
+	if op, ok := calcUnary[tok.text]; ok {
+		value, err := c.calcTerm()
+		return op(value), err
+	}
+
+	if tok.text == "HERE" {
+		return int64(c.here), nil
+	}
+
+	return c.value(tok)
+}
+
+func safeDiv(a, b int64, mod bool) (int64, bool) {
+	if b == 0 {
+		return 0, false
+	}
+
+	if mod {
+		return a % b, true
+	}
+	return a / b, true
+}
+
+func minInt(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
+func pow(a, b int64) (int64, bool) {
+	if b < 0 {
+		return 0, false
+	}
+
+	value := int64(1)
+	for ; b > 0; b-- {
+		value *= a
+	}
+
+	return value, true
+}
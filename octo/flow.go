@@ -0,0 +1,182 @@
+package octo
+
+// Opcodes that skip the next instruction when the condition is true, by the condition
+// The condition "vx key" is true when the key vx is down
+var skipWhen = map[string][2]uint16{
+	// condition: {against a value, against a register}
+	"==":   {0x3000, 0x5000},
+	"!=":   {0x4000, 0x9000},
+	"key":  {0xE09E, 0xE09E},
+	"-key": {0xE0A1, 0xE0A1},
+}
+
+// Negation of conditions
+var negation = map[string]string{"==": "!=", "!=": "==", "key": "-key", "-key": "key"}
+
+// condition is a comparison of register x with a value or register y
+type condition struct {
+	op       string
+	x        byte
+	y        byte
+	register bool
+	value    int64
+}
+
+// ifStatement compiles "if cond then" and "if cond begin"
+func (c *compiler) ifStatement(tok token) error {
+	cond, err := c.condition()
+	if err != nil {
+		return err
+	}
+
+	next := c.next()
+	switch next.text {
+	case "then":
+		// Skips the next statement when the condition is false
+		c.skip(cond, false)
+		return nil
+	case "begin":
+		// Skips the jump to else or end when the condition is true
+		c.skip(cond, true)
+		c.blocks = append(c.blocks, &block{jump: c.here, tok: tok})
+		c.emit(0x1000)
+		return nil
+	}
+
+	return errorf(next, "then or begin expected")
+}
+
+// elseStatement ends the body of "if ... begin", jumping over the body of else
+func (c *compiler) elseStatement(tok token) error {
+	if len(c.blocks) == 0 || c.blocks[len(c.blocks)-1].loop {
+		return errorf(tok, "else without begin")
+	}
+
+	b := c.blocks[len(c.blocks)-1]
+	jump := c.here
+	c.emit(0x1000)
+	c.patch(b.jump, c.here)
+	b.jump = jump
+	return nil
+}
+
+// whileStatement compiles "while cond", that jumps to the end of loop when the condition is false
+func (c *compiler) whileStatement(tok token) error {
+	var loop *block
+	for idx := len(c.blocks) - 1; idx >= 0 && loop == nil; idx-- {
+		if c.blocks[idx].loop {
+			loop = c.blocks[idx]
+		}
+	}
+	if loop == nil {
+		return errorf(tok, "while without loop")
+	}
+
+	cond, err := c.condition()
+	if err != nil {
+		return err
+	}
+
+	c.skip(cond, true)
+	loop.breaks = append(loop.breaks, c.here)
+	c.emit(0x1000)
+	return nil
+}
+
+// popBlock removes the innermost block, that must be a loop when loop is true
+func (c *compiler) popBlock(tok token, loop bool) (*block, error) {
+	if len(c.blocks) == 0 || c.blocks[len(c.blocks)-1].loop != loop {
+		if loop {
+			return nil, errorf(tok, "again without loop")
+		}
+		return nil, errorf(tok, "end without begin")
+	}
+
+	b := c.blocks[len(c.blocks)-1]
+	c.blocks = c.blocks[:len(c.blocks)-1]
+	return b, nil
+}
+
+// condition parses "vx key", "vx -key" and "vx OP (vy|value)" with OP one of == != < > <= >=
+func (c *compiler) condition() (condition, error) {
+	x, err := c.register(c.next())
+	if err != nil {
+		return condition{}, err
+	}
+
+	op := c.next()
+	cond := condition{op: op.text, x: x}
+	switch op.text {
+	case "key", "-key":
+		return cond, nil
+	case "==", "!=", "<", ">", "<=", ">=":
+	default:
+		return condition{}, errorf(op, "invalid comparison %s", op.text)
+	}
+
+	rhs := c.next()
+	if y, err := c.register(rhs); err == nil {
+		cond.y, cond.register = y, true
+	} else {
+		value, err := c.number(rhs, 0xFF)
+		if err != nil {
+			return condition{}, err
+		}
+		cond.value = int64(value)
+	}
+
+	ordering := op.text != "==" && op.text != "!="
+	if ordering && (cond.x == 0xF || (cond.register && cond.y == 0xF)) {
+		return condition{}, errorf(op, "vf can not be compared by %s", op.text)
+	}
+
+	return cond, nil
+}
+
+// skip emits the instructions that skip the next one when the condition is equal to when
+func (c *compiler) skip(cond condition, when bool) {
+	switch cond.op {
+	case "<", ">", "<=", ">=":
+		cond = c.compare(cond)
+	}
+
+	op := cond.op
+	if !when {
+		op = negation[op]
+	}
+
+	if cond.register {
+		c.emit(skipWhen[op][1] | uint16(cond.x)<<8 | uint16(cond.y)<<4)
+		return
+	}
+
+	c.emit(skipWhen[op][0] | uint16(cond.x)<<8 | uint16(cond.value))
+}
+
+// compare emits the comparisons < > <= >= on vf and returns the condition on vf that is equal to it
+// vf gets the flag of a subtraction, that is 1 when there is no borrow: "vf := l  vf -= r" is l >= r
+func (c *compiler) compare(cond condition) condition {
+	x, y := uint16(cond.x), uint16(cond.y)
+	lowerFirst := cond.op == "<" || cond.op == ">="
+
+	switch {
+	case cond.register && lowerFirst:
+		c.emit(0x8F00 | x<<4)
+		c.emit(0x8F05 | y<<4)
+	case cond.register:
+		c.emit(0x8F00 | y<<4)
+		c.emit(0x8F05 | x<<4)
+	case lowerFirst:
+		c.emit(0x6F00 | uint16(cond.value))
+		c.emit(0x8F07 | x<<4)
+	default:
+		c.emit(0x6F00 | uint16(cond.value))
+		c.emit(0x8F05 | x<<4)
+	}
+
+	// vf is 1 when the comparison is >= or <=, and 0 when it is < or >
+	if cond.op == "<" || cond.op == ">" {
+		return condition{op: "==", x: 0xF}
+	}
+	return condition{op: "!=", x: 0xF}
+}
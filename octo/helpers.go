@@ -0,0 +1,250 @@
+package octo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+func (c *compiler) done() bool {
+	return c.idx >= len(c.tokens)
+}
+
+// next returns the next token, that is empty on the end of source
+func (c *compiler) next() token {
+	if c.done() {
+		end := c.last()
+		return token{line: end.line, col: end.col + len(end.text)}
+	}
+
+	c.idx++
+	return c.tokens[c.idx-1]
+}
+
+func (c *compiler) peek() token {
+	if c.done() {
+		return token{}
+	}
+
+	return c.tokens[c.idx]
+}
+
+// last returns the last token of source
+func (c *compiler) last() token {
+	if len(c.tokens) == 0 {
+		return token{line: 1, col: 1}
+	}
+
+	return c.tokens[len(c.tokens)-1]
+}
+
+func (c *compiler) expect(text string) error {
+	if tok := c.next(); tok.text != text {
+		return errorf(tok, "%s expected", text)
+	}
+
+	return nil
+}
+
+// name returns the next token, that must be a name that is not a register
+func (c *compiler) name() (token, error) {
+	tok := c.next()
+	if !isName(tok.text) {
+		return tok, errorf(tok, "name expected")
+	}
+
+	if _, err := c.register(tok); err == nil {
+		return tok, errorf(tok, "%s is a register", tok.text)
+	}
+
+	return tok, nil
+}
+
+// register returns the register v0..vf, or the register aliased by tok
+func (c *compiler) register(tok token) (byte, error) {
+	if x, ok := c.aliases[tok.text]; ok {
+		return x, nil
+	}
+
+	text := strings.ToLower(tok.text)
+	if len(text) == 2 && text[0] == 'v' {
+		if x, err := strconv.ParseUint(text[1:], 16, 4); err == nil {
+			return byte(x), nil
+		}
+	}
+
+	return 0, errorf(tok, "register expected")
+}
+
+// literal returns the number of tok, that is decimal or prefixed by 0x or 0b
+func (c *compiler) literal(tok token) (int64, error) {
+	text := strings.TrimPrefix(tok.text, "-")
+	base := 10
+	if strings.HasPrefix(text, "0x") || strings.HasPrefix(text, "0b") {
+		base = 0
+	}
+
+	value, err := strconv.ParseInt(text, base, 64)
+	if err != nil {
+		return 0, errorf(tok, "invalid number %s", tok.text)
+	}
+
+	if strings.HasPrefix(tok.text, "-") {
+		return -value, nil
+	}
+	return value, nil
+}
+
+// value returns the value of a number, a constant or a label already defined
+func (c *compiler) value(tok token) (int64, error) {
+	if value, ok := c.consts[tok.text]; ok {
+		return value, nil
+	}
+
+	if addr, ok := c.labels[tok.text]; ok {
+		return int64(addr), nil
+	}
+
+	if tok.text == "" {
+		return 0, errorf(tok, "unexpected end of source")
+	}
+
+	if isName(tok.text) {
+		return 0, errorf(tok, "undefined name %s", tok.text)
+	}
+
+	return c.literal(tok)
+}
+
+// number returns the value of tok, that must be between 0 and max
+// When max is 0xFF the bytes may be negative
+func (c *compiler) number(tok token, max uint16) (uint16, error) {
+	value, err := c.value(tok)
+	if err != nil {
+		return 0, err
+	}
+
+	if max == 0xFF && value >= -0x80 && value < 0 {
+		return uint16(value) & 0xFF, nil
+	}
+
+	if value < 0 || value > int64(max) {
+		return 0, errorf(tok, "value %d out of range 0..%d", value, max)
+	}
+
+	return uint16(value), nil
+}
+
+func (c *compiler) emit(word uint16) {
+	c.write(c.here, byte(word>>8))
+	c.write(c.here+1, byte(word))
+	c.here += 2
+}
+
+func (c *compiler) emitByte(value int64, tok token) error {
+	if value < -0x80 || value > 0xFF {
+		return errorf(tok, "value %d out of range -128..255", value)
+	}
+
+	c.write(c.here, byte(value))
+	c.here++
+	return nil
+}
+
+// emitAddress emits opcode with the address of tok, that is written later when it is a label not defined yet
+func (c *compiler) emitAddress(opcode uint16, tok token) error {
+	if isName(tok.text) && !c.isDefined(tok) {
+		c.fixupAt(c.here, fixupAddress, tok)
+		c.emit(opcode)
+		return nil
+	}
+
+	addr, err := c.number(tok, 0xFFF)
+	if err != nil {
+		return err
+	}
+
+	c.emit(opcode | addr)
+	return nil
+}
+
+func (c *compiler) write(addr int, b byte) {
+	idx := addr - c.origin
+	for len(c.rom) <= idx {
+		c.rom = append(c.rom, 0)
+	}
+
+	c.rom[idx] = b
+}
+
+func (c *compiler) read(addr int) byte {
+	return c.rom[addr-c.origin]
+}
+
+// patch writes the address target on the jump at addr
+func (c *compiler) patch(addr, target int) {
+	c.write(addr, c.read(addr)&0xF0|byte(target>>8)&0x0F)
+	c.write(addr+1, byte(target))
+}
+
+// isDefined returns true when tok is a constant or a label already defined
+func (c *compiler) isDefined(tok token) bool {
+	_, constant := c.consts[tok.text]
+	_, label := c.labels[tok.text]
+	return constant || label
+}
+
+func (c *compiler) fixupAt(addr, kind int, tok token) {
+	c.fixups[tok.text] = append(c.fixups[tok.text], fixup{addr: addr, kind: kind, tok: tok})
+}
+
+// label defines the label name on addr, writing it where it was used before
+func (c *compiler) label(name token, addr int) error {
+	if c.isDefined(name) {
+		return errorf(name, "%s already defined", name.text)
+	}
+
+	if addr > 0xFFFF {
+		return errorf(name, "address 0x%X out of memory", addr)
+	}
+	c.labels[name.text] = uint16(addr)
+
+	for _, f := range c.fixups[name.text] {
+		switch f.kind {
+		case fixupAddress:
+			if addr > 0xFFF {
+				return errorf(f.tok, "address 0x%X of %s out of range 0..0xFFF, use i := long", addr, name.text)
+			}
+			c.patch(f.addr, addr)
+		case fixupLong:
+			c.write(f.addr, byte(addr>>8))
+			c.write(f.addr+1, byte(addr))
+		case fixupHigh:
+			c.write(f.addr, c.read(f.addr)|byte(addr>>8)&0x0F)
+		case fixupLow:
+			c.write(f.addr, byte(addr))
+		}
+	}
+	delete(c.fixups, name.text)
+
+	return nil
+}
+
+// isName returns true when text may be the name of a label, constant or macro
+func isName(text string) bool {
+	if text == "" || (text[0] >= '0' && text[0] <= '9') || text[0] == '-' {
+		return false
+	}
+
+	for _, r := range text {
+		if !(r == '_' || r == '-' || r == '.' || (r >= '0' && r <= '9') || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func errorf(tok token, format string, args ...interface{}) *Error {
+	return &Error{Line: tok.line, Column: tok.col, Msg: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,45 @@
+package octo
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+type token struct {
+	text string
+	line int
+	col  int
+}
+
+// lex splits src into the tokens separated by spaces, dropping the comments from # to the end of line
+func lex(src io.Reader) ([]token, error) {
+	tokens := []token{}
+
+	scanner := bufio.NewScanner(src)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if idx := strings.IndexByte(text, '#'); idx >= 0 {
+			text = text[:idx]
+		}
+
+		for i := 0; i < len(text); {
+			if isSpace(text[i]) {
+				i++
+				continue
+			}
+
+			start := i
+			for i < len(text) && !isSpace(text[i]) {
+				i++
+			}
+			tokens = append(tokens, token{text: text[start:i], line: line, col: start + 1})
+		}
+	}
+
+	return tokens, scanner.Err()
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r'
+}
@@ -0,0 +1,617 @@
+package octo
+
+import (
+	"fmt"
+	"io"
+)
+
+const defaultOrigin = 0x200
+
+// maxMacroExpansions limits the macros expanded, to stop on recursive macros
+const maxMacroExpansions = 1 << 16
+
+// Error is an error on source, at Line and Column (both starting on 1)
+type Error struct {
+	File   string
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *Error) Error() string {
+	if e.File == "" {
+		return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Msg)
+	}
+
+	return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Column, e.Msg)
+}
+
+// Program is the result of compilation
+type Program struct {
+	// Rom is the binary of program, loaded on the origin address
+	Rom []byte
+
+	// Symbols are the addresses of labels
+	Symbols map[string]uint16
+
+	// Breakpoints are the addresses marked by :breakpoint
+	Breakpoints map[string]uint16
+}
+
+// Compiler turns a source of Octo language into a ROM
+//
+// It supports the instructions of CHIP-8, SUPER-CHIP and the XO-CHIP ones that Cpu runs, labels,
+// :const, :alias, :calc, :macro, :byte, :org, :next, :unpack, :call, :breakpoint, the structured
+// flow "if ... then", "if ... begin ... else ... end" and "loop ... while ... again",
+// and the comparisons < > <= >= (that use vf)
+//
+// As Octo, when the program does not start with ": main" its first instruction is a jump to main
+type Compiler struct {
+	origin uint16
+}
+
+type ConfigCompiler struct {
+	// Address where ROM is loaded, when zero it is 0x200
+	Origin uint16
+}
+
+// NewCompiler is a function that receive a config as param and return a pointer to Compiler
+func NewCompiler(config *ConfigCompiler) *Compiler {
+	origin := config.Origin
+	if origin == 0 {
+		origin = defaultOrigin
+	}
+
+	return &Compiler{origin: origin}
+}
+
+// Opcodes of the statements on a register vx, by the statement
+var registerOpcodes = map[string]uint16{
+	"bcd": 0xF033, "saveflags": 0xF075, "loadflags": 0xF085,
+	"delay": 0xF015, "buzzer": 0xF018, "pitch": 0xF03A,
+}
+
+// N of the instructions 8XYN, by the operator of "vx OP vy"
+var operatorCodes = map[string]uint16{
+	":=": 0x0, "|=": 0x1, "&=": 0x2, "^=": 0x3, "+=": 0x4, "-=": 0x5, ">>=": 0x6, "=-": 0x7, "<<=": 0xE,
+}
+
+type macro struct {
+	params []string
+	body   []token
+}
+
+// Kinds of values written after the label is defined
+const (
+	fixupAddress = iota // the low 12 bits of an instruction
+	fixupLong           // a word
+	fixupHigh           // the high nibble of address on the low nibble of a byte
+	fixupLow            // the low byte of address
+)
+
+type fixup struct {
+	addr int
+	kind int
+	tok  token
+}
+
+// block is an "if ... begin" or a "loop" being compiled
+type block struct {
+	loop   bool
+	start  int
+	jump   int
+	breaks []int
+	tok    token
+}
+
+// compiler holds the state of one call to Compile
+type compiler struct {
+	origin      int
+	tokens      []token
+	idx         int
+	expansions  int
+	rom         []byte
+	here        int
+	labels      map[string]uint16
+	consts      map[string]int64
+	aliases     map[string]byte
+	macros      map[string]*macro
+	fixups      map[string][]fixup
+	blocks      []*block
+	breakpoints map[string]uint16
+}
+
+// Compile returns the program of src, name is the file of src used on errors
+// The error returned is an *Error when it is on source
+func (o *Compiler) Compile(name string, src io.Reader) (*Program, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &compiler{
+		origin:      int(o.origin),
+		tokens:      tokens,
+		here:        int(o.origin),
+		labels:      map[string]uint16{},
+		consts:      map[string]int64{},
+		aliases:     map[string]byte{},
+		macros:      map[string]*macro{},
+		fixups:      map[string][]fixup{},
+		breakpoints: map[string]uint16{},
+	}
+
+	if err := c.compile(); err != nil {
+		if e, ok := err.(*Error); ok {
+			e.File = name
+		}
+		return nil, err
+	}
+
+	return &Program{Rom: c.rom, Symbols: c.labels, Breakpoints: c.breakpoints}, nil
+}
+
+func (c *compiler) compile() error {
+	// Reserves the jump to main, unless the program starts on it
+	if len(c.tokens) < 2 || c.tokens[0].text != ":" || c.tokens[1].text != "main" {
+		c.emitAddress(0x1000, token{text: "main", line: 1, col: 1})
+	}
+
+	for !c.done() {
+		if err := c.statement(); err != nil {
+			return err
+		}
+	}
+
+	if len(c.blocks) > 0 {
+		b := c.blocks[len(c.blocks)-1]
+		if b.loop {
+			return errorf(b.tok, "loop without again")
+		}
+		return errorf(b.tok, "begin without end")
+	}
+
+	// Reports the first use of a label not defined
+	var undefined *fixup
+	for _, fixups := range c.fixups {
+		f := fixups[0]
+		if undefined == nil || f.tok.line < undefined.tok.line || (f.tok.line == undefined.tok.line && f.tok.col < undefined.tok.col) {
+			undefined = &f
+		}
+	}
+	if undefined != nil {
+		return errorf(undefined.tok, "undefined label %s", undefined.tok.text)
+	}
+
+	if c.origin+len(c.rom) > 0x10000 {
+		return errorf(c.last(), "program beyond the address 0xFFFF")
+	}
+
+	return nil
+}
+
+func (c *compiler) statement() error {
+	tok := c.next()
+
+	switch tok.text {
+	case ":":
+		name, err := c.name()
+		if err != nil {
+			return err
+		}
+		return c.label(name, c.here)
+	case ":const":
+		name, err := c.name()
+		if err != nil {
+			return err
+		}
+		value, err := c.value(c.next())
+		if err != nil {
+			return err
+		}
+		c.consts[name.text] = value
+	case ":calc":
+		name, err := c.name()
+		if err != nil {
+			return err
+		}
+		if err := c.expect("{"); err != nil {
+			return err
+		}
+		value, err := c.calc()
+		if err != nil {
+			return err
+		}
+		c.consts[name.text] = value
+	case ":alias":
+		name, err := c.name()
+		if err != nil {
+			return err
+		}
+		x, err := c.register(c.next())
+		if err != nil {
+			return err
+		}
+		c.aliases[name.text] = x
+	case ":macro":
+		return c.macro()
+	case ":byte":
+		var value int64
+		var err error
+		if c.peek().text == "{" {
+			c.next()
+			value, err = c.calc()
+		} else {
+			value, err = c.value(c.next())
+		}
+		if err != nil {
+			return err
+		}
+		return c.emitByte(value, tok)
+	case ":org":
+		value, err := c.value(c.next())
+		if err != nil {
+			return err
+		}
+		if value < int64(c.origin) || value > 0xFFFF {
+			return errorf(tok, "address 0x%X out of memory", value)
+		}
+		c.here = int(value)
+	case ":next":
+		name, err := c.name()
+		if err != nil {
+			return err
+		}
+		return c.label(name, c.here+1)
+	case ":unpack":
+		return c.unpack()
+	case ":call":
+		return c.emitAddress(0x2000, c.next())
+	case ":breakpoint":
+		name, err := c.name()
+		if err != nil {
+			return err
+		}
+		c.breakpoints[name.text] = uint16(c.here)
+	case ":monitor":
+		c.next()
+		c.next()
+	case "clear":
+		c.emit(0x00E0)
+	case "return", ";":
+		c.emit(0x00EE)
+	case "hires":
+		c.emit(0x00FF)
+	case "lores":
+		c.emit(0x00FE)
+	case "exit":
+		c.emit(0x00FD)
+	case "scroll-left":
+		c.emit(0x00FC)
+	case "scroll-right":
+		c.emit(0x00FB)
+	case "audio":
+		c.emit(0xF002)
+	case "scroll-down":
+		n, err := c.number(c.next(), 0xF)
+		if err != nil {
+			return err
+		}
+		c.emit(0x00C0 | n)
+	case "scroll-up":
+		n, err := c.number(c.next(), 0xF)
+		if err != nil {
+			return err
+		}
+		c.emit(0x00D0 | n)
+	case "plane":
+		n, err := c.number(c.next(), 0x3)
+		if err != nil {
+			return err
+		}
+		c.emit(0xF001 | n<<8)
+	case "bcd", "saveflags", "loadflags":
+		x, err := c.register(c.next())
+		if err != nil {
+			return err
+		}
+		c.emit(registerOpcodes[tok.text] | uint16(x)<<8)
+	case "save", "load":
+		return c.saveLoad(tok)
+	case "sprite":
+		x, err := c.register(c.next())
+		if err != nil {
+			return err
+		}
+		y, err := c.register(c.next())
+		if err != nil {
+			return err
+		}
+		n, err := c.number(c.next(), 0xF)
+		if err != nil {
+			return err
+		}
+		c.emit(0xD000 | uint16(x)<<8 | uint16(y)<<4 | n)
+	case "jump":
+		return c.emitAddress(0x1000, c.next())
+	case "jump0":
+		return c.emitAddress(0xB000, c.next())
+	case "i":
+		return c.indexStatement()
+	case "delay", "buzzer", "pitch":
+		if err := c.expect(":="); err != nil {
+			return err
+		}
+		x, err := c.register(c.next())
+		if err != nil {
+			return err
+		}
+		c.emit(registerOpcodes[tok.text] | uint16(x)<<8)
+	case "if":
+		return c.ifStatement(tok)
+	case "else":
+		return c.elseStatement(tok)
+	case "end":
+		b, err := c.popBlock(tok, false)
+		if err != nil {
+			return err
+		}
+		c.patch(b.jump, c.here)
+	case "loop":
+		c.blocks = append(c.blocks, &block{loop: true, start: c.here, tok: tok})
+	case "while":
+		return c.whileStatement(tok)
+	case "again":
+		b, err := c.popBlock(tok, true)
+		if err != nil {
+			return err
+		}
+		c.emit(0x1000 | uint16(b.start&0xFFF))
+		for _, addr := range b.breaks {
+			c.patch(addr, c.here)
+		}
+	default:
+		return c.other(tok)
+	}
+
+	return nil
+}
+
+// other compiles the statements that start by a name: register operations, macros, calls and bytes
+func (c *compiler) other(tok token) error {
+	if _, err := c.register(tok); err == nil {
+		return c.registerStatement(tok)
+	}
+
+	if m, ok := c.macros[tok.text]; ok {
+		return c.expand(tok, m)
+	}
+
+	if value, err := c.literal(tok); err == nil {
+		return c.emitByte(value, tok)
+	}
+
+	if value, ok := c.consts[tok.text]; ok {
+		return c.emitByte(value, tok)
+	}
+
+	if !isName(tok.text) {
+		return errorf(tok, "unexpected %s", tok.text)
+	}
+
+	return c.emitAddress(0x2000, tok)
+}
+
+func (c *compiler) registerStatement(tok token) error {
+	x, _ := c.register(tok)
+	op := c.next()
+	rhs := c.next()
+
+	if y, err := c.register(rhs); err == nil {
+		code, ok := operatorCodes[op.text]
+		if !ok {
+			return errorf(op, "unexpected %s", op.text)
+		}
+		c.emit(0x8000 | uint16(x)<<8 | uint16(y)<<4 | code)
+		return nil
+	}
+
+	switch {
+	case op.text == ":=" && rhs.text == "random":
+		nn, err := c.number(c.next(), 0xFF)
+		if err != nil {
+			return err
+		}
+		c.emit(0xC000 | uint16(x)<<8 | nn)
+	case op.text == ":=" && rhs.text == "delay":
+		c.emit(0xF007 | uint16(x)<<8)
+	case op.text == ":=" && rhs.text == "key":
+		c.emit(0xF00A | uint16(x)<<8)
+	case op.text == ":=", op.text == "+=", op.text == "-=":
+		value, err := c.value(rhs)
+		if err != nil {
+			return err
+		}
+		if op.text == "-=" {
+			value = -value
+		}
+		if value < -0x80 || value > 0xFF {
+			return errorf(rhs, "value %d out of range -128..255", value)
+		}
+
+		opcode := uint16(0x6000)
+		if op.text != ":=" {
+			opcode = 0x7000
+		}
+		c.emit(opcode | uint16(x)<<8 | uint16(value)&0xFF)
+	default:
+		return errorf(op, "unexpected %s", op.text)
+	}
+
+	return nil
+}
+
+func (c *compiler) indexStatement() error {
+	op := c.next()
+	rhs := c.next()
+
+	switch {
+	case op.text == "+=":
+		x, err := c.register(rhs)
+		if err != nil {
+			return err
+		}
+		c.emit(0xF01E | uint16(x)<<8)
+	case op.text == ":=" && (rhs.text == "hex" || rhs.text == "bighex"):
+		x, err := c.register(c.next())
+		if err != nil {
+			return err
+		}
+		if rhs.text == "hex" {
+			c.emit(0xF029 | uint16(x)<<8)
+		} else {
+			c.emit(0xF030 | uint16(x)<<8)
+		}
+	case op.text == ":=" && rhs.text == "long":
+		c.emit(0xF000)
+		addr := c.next()
+		if value, ok := c.labels[addr.text]; ok {
+			c.emit(value)
+			return nil
+		}
+		if value, err := c.value(addr); err == nil && value >= 0 && value <= 0xFFFF {
+			c.emit(uint16(value))
+			return nil
+		}
+		if !isName(addr.text) {
+			return errorf(addr, "address expected")
+		}
+		c.fixupAt(c.here, fixupLong, addr)
+		c.emit(0x0000)
+	case op.text == ":=":
+		return c.emitAddress(0xA000, rhs)
+	default:
+		return errorf(op, "unexpected %s", op.text)
+	}
+
+	return nil
+}
+
+// saveLoad compiles "save vx", "load vx" and the ranges "save vx - vy", "load vx - vy"
+func (c *compiler) saveLoad(tok token) error {
+	x, err := c.register(c.next())
+	if err != nil {
+		return err
+	}
+
+	if c.peek().text != "-" {
+		if tok.text == "save" {
+			c.emit(0xF055 | uint16(x)<<8)
+		} else {
+			c.emit(0xF065 | uint16(x)<<8)
+		}
+		return nil
+	}
+
+	c.next()
+	y, err := c.register(c.next())
+	if err != nil {
+		return err
+	}
+
+	if tok.text == "save" {
+		c.emit(0x5002 | uint16(x)<<8 | uint16(y)<<4)
+	} else {
+		c.emit(0x5003 | uint16(x)<<8 | uint16(y)<<4)
+	}
+	return nil
+}
+
+// unpack compiles ":unpack N label" to "v0 := N << 4 | label >> 8" and "v1 := label"
+func (c *compiler) unpack() error {
+	n, err := c.number(c.next(), 0xF)
+	if err != nil {
+		return err
+	}
+
+	tok := c.next()
+	if isName(tok.text) && !c.isDefined(tok) {
+		c.emit(0x6000 | n<<4)
+		c.fixupAt(c.here-1, fixupHigh, tok)
+		c.emit(0x6100)
+		c.fixupAt(c.here-1, fixupLow, tok)
+		return nil
+	}
+
+	addr, err := c.number(tok, 0xFFF)
+	if err != nil {
+		return err
+	}
+
+	c.emit(0x6000 | n<<4 | addr>>8)
+	c.emit(0x6100 | addr&0xFF)
+	return nil
+}
+
+func (c *compiler) macro() error {
+	name, err := c.name()
+	if err != nil {
+		return err
+	}
+
+	m := &macro{}
+	for !c.done() && c.peek().text != "{" {
+		m.params = append(m.params, c.next().text)
+	}
+	if err := c.expect("{"); err != nil {
+		return err
+	}
+
+	for depth := 1; ; {
+		if c.done() {
+			return errorf(name, "macro %s without }", name.text)
+		}
+
+		tok := c.next()
+		switch tok.text {
+		case "{":
+			depth++
+		case "}":
+			depth--
+		}
+		if depth == 0 {
+			break
+		}
+		m.body = append(m.body, tok)
+	}
+
+	c.macros[name.text] = m
+	return nil
+}
+
+// expand replaces the invocation of macro by its body, with the params replaced by the arguments
+func (c *compiler) expand(tok token, m *macro) error {
+	c.expansions++
+	if c.expansions > maxMacroExpansions {
+		return errorf(tok, "macro %s expanded too many times", tok.text)
+	}
+
+	args := map[string]string{}
+	for _, param := range m.params {
+		if c.done() {
+			return errorf(tok, "macro %s expects %d arguments", tok.text, len(m.params))
+		}
+		args[param] = c.next().text
+	}
+
+	body := make([]token, len(m.body))
+	for idx, t := range m.body {
+		if arg, ok := args[t.text]; ok {
+			t.text = arg
+		}
+		body[idx] = t
+	}
+
+	c.tokens, c.idx = append(body, c.tokens[c.idx:]...), 0
+	return nil
+}
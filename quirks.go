@@ -0,0 +1,43 @@
+package chip8
+
+// Quirks selects how Cpu interprets the opcodes whose behavior differs between CHIP-8 platforms.
+// The zero value keeps the behavior of QuirksModern
+type Quirks struct {
+	// ShiftUsesVY makes 8XY6 and 8XYE shift VY and store the result in VX, instead of shifting VX in place
+	ShiftUsesVY bool
+
+	// LoadStoreIncrementsI makes FX55 and FX65 leave I pointing after the last register saved or loaded
+	LoadStoreIncrementsI bool
+
+	// JumpUsesVX makes BNNN behave as BXNN, jumping to XNN + VX instead of NNN + V0
+	JumpUsesVX bool
+
+	// VFReset makes 8XY1, 8XY2 and 8XY3 reset VF to 0
+	VFReset bool
+
+	// Clipping makes DXYN clip the sprites on the edges of the screen instead of wrapping them around
+	Clipping bool
+}
+
+// QuirksVIP is the behavior of the original interpreter of COSMAC VIP
+var QuirksVIP = Quirks{
+	ShiftUsesVY:          true,
+	LoadStoreIncrementsI: true,
+	VFReset:              true,
+	Clipping:             true,
+}
+
+// QuirksSCHIP is the behavior of SUPER-CHIP 1.1 on HP48 calculators
+var QuirksSCHIP = Quirks{
+	JumpUsesVX: true,
+	Clipping:   true,
+}
+
+// QuirksXOCHIP is the behavior of XO-CHIP, as implemented by Octo
+var QuirksXOCHIP = Quirks{
+	ShiftUsesVY:          true,
+	LoadStoreIncrementsI: true,
+}
+
+// QuirksModern is the behavior expected by most ROMs written for modern interpreters
+var QuirksModern = Quirks{}
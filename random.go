@@ -0,0 +1,42 @@
+package chip8
+
+import "math/rand"
+
+// Random is the source of random numbers of instruction CXNN
+// It is implemented by *rand.Rand, so rand.New(rand.NewSource(seed)) is a seedable Random
+type Random interface {
+	/*
+		Intn should return a random number in [0, n)
+	*/
+	Intn(n int) int
+}
+
+// SequenceRandom implements Random returning a scripted sequence of numbers, repeated when it ends
+type SequenceRandom struct {
+	values []byte
+	next   int
+}
+
+// NewSequenceRandom returns a pointer to SequenceRandom that returns values in order
+func NewSequenceRandom(values ...byte) *SequenceRandom {
+	return &SequenceRandom{values: values}
+}
+
+// Intn returns the next number of sequence modulo n
+func (sr *SequenceRandom) Intn(n int) int {
+	if len(sr.values) == 0 {
+		return 0
+	}
+
+	value := int(sr.values[sr.next]) % n
+	sr.next = (sr.next + 1) % len(sr.values)
+
+	return value
+}
+
+// globalRandom implements Random with the global source of math/rand
+type globalRandom struct{}
+
+func (globalRandom) Intn(n int) int {
+	return rand.Intn(n)
+}
@@ -0,0 +1,285 @@
+package chip8
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"io"
+	"sync"
+)
+
+// RecordFormat is the format of the recordings of Recorder
+type RecordFormat int
+
+const (
+	// RecordGIF records an animated GIF, written when the recording stops
+	RecordGIF RecordFormat = iota
+
+	// RecordY4M records a raw YUV4MPEG2 video of 60 frames per second, written during the recording
+	RecordY4M
+)
+
+// ConfigRecorder sets the display recorded and the format of recordings
+type ConfigRecorder struct {
+	// Framebuffer is the display recorded
+	Framebuffer Framebuffer
+
+	// Format of recordings, when zero it is RecordGIF
+	Format RecordFormat
+
+	// Image sets the colors and scale of frames
+	// The frames have always the size of high resolution, so the pixels of low resolution are doubled
+	Image ConfigImage
+}
+
+// Recorder captures the frames of a Framebuffer on each Flush into a video
+// Its methods Flush and FrameEnd are the hooks DisplayFlush and FrameEnd of Cpu, and the frontend controls
+// the recording by Start and Stop
+// The time of each frame is the emulated time of the flush, so the video keeps the speed of the game at 60 Hz,
+// and the last frame lasts until the last frame processed before Stop
+type Recorder struct {
+	mu          sync.Mutex
+	framebuffer Framebuffer
+	format      RecordFormat
+	palette     color.Palette
+	scale       int
+
+	w         io.Writer
+	recording bool
+	err       error
+
+	// pending is the last frame captured, not written yet because its duration is unknown
+	// shown is true when part of its duration is already written
+	pending *image.Paletted
+	shown   bool
+	version uint64
+
+	// start is the frame of first capture, frame is the frame of pending and offset fixes the frames after a reset
+	start, frame, offset uint64
+
+	// now is the frames processed, received by FrameEnd
+	now uint64
+
+	// GIF being recorded and the centiseconds of its frames
+	gif   *gif.GIF
+	delay int
+
+	// yuv is the palette converted to Y'CbCr and frame is pending as a Y4M frame
+	yuv   [][3]byte
+	video []byte
+}
+
+// NewRecorder returns a Recorder of config.Framebuffer, it does not record until Start
+func NewRecorder(config *ConfigRecorder) *Recorder {
+	palette := config.Image.Palette
+	if len(palette) == 0 {
+		palette = DefaultPalette
+	}
+
+	scale := config.Image.Scale
+	if scale <= 0 {
+		scale = 1
+	}
+
+	yuv := make([][3]byte, len(palette))
+	for i, c := range palette {
+		r, g, b, _ := c.RGBA()
+		yuv[i][0], yuv[i][1], yuv[i][2] = color.RGBToYCbCr(uint8(r>>8), uint8(g>>8), uint8(b>>8))
+	}
+
+	return &Recorder{
+		framebuffer: config.Framebuffer,
+		format:      config.Format,
+		palette:     palette,
+		scale:       scale,
+		yuv:         yuv,
+	}
+}
+
+// Start starts a recording written on w, it returns ErrRecording when a recording is in progress
+func (r *Recorder) Start(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.recording {
+		return ErrRecording
+	}
+
+	r.w, r.recording, r.err = w, true, nil
+	r.pending, r.start, r.frame, r.offset, r.now, r.delay = nil, 0, 0, 0, 0, 0
+
+	width, height := hiResScreenWidth*r.scale, hiResScreenHeight*r.scale
+	if r.format == RecordY4M {
+		// C444 keeps the colors of each pixel, as the frames are not subsampled
+		_, r.err = fmt.Fprintf(w, "YUV4MPEG2 W%d H%d F60:1 Ip A1:1 C444\n", width, height)
+		return r.err
+	}
+
+	r.gif = &gif.GIF{Config: image.Config{ColorModel: r.palette, Width: width, Height: height}}
+	return nil
+}
+
+// Stop ends the recording, writing the frames left, and returns the first error of writing
+// It returns nil when there is no recording in progress
+func (r *Recorder) Stop() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.recording {
+		return nil
+	}
+	r.recording = false
+
+	if r.err != nil {
+		return r.err
+	}
+
+	// A recording without flushes has the current frame
+	if r.pending == nil {
+		if r.err = r.capture(); r.err != nil {
+			return r.err
+		}
+	}
+
+	// The last frame lasts until the frames processed, at least one frame when it is not shown yet
+	end := r.frame
+	if r.now > end {
+		end = r.now
+	}
+	if end == r.frame && !r.shown {
+		end++
+	}
+
+	r.write(end)
+	if r.err == nil && r.format == RecordGIF {
+		r.err = gif.EncodeAll(r.w, r.gif)
+	}
+	r.gif, r.pending, r.w = nil, nil, nil
+
+	return r.err
+}
+
+// Recording returns true when a recording is in progress
+func (r *Recorder) Recording() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.recording
+}
+
+// Flush captures the frame of framebuffer flushed after frames of emulated time, it is the hook DisplayFlush
+// A flush without changes on framebuffer extends the previous frame, and the flushes on the same frame replace it
+func (r *Recorder) Flush(frames uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.recording || r.err != nil {
+		return
+	}
+
+	if r.pending == nil {
+		frames += r.offset
+		r.start, r.frame = frames, frames
+		r.err = r.capture()
+		return
+	}
+
+	frames = r.emulated(frames)
+	if r.framebuffer.Version() == r.version {
+		return
+	}
+
+	if frames > r.frame {
+		r.write(frames)
+		r.frame = frames
+	}
+	if r.err == nil {
+		r.err = r.capture()
+	}
+}
+
+// FrameEnd receives the frames processed at the end of each frame, it is the hook FrameEnd
+// The frame captured lasts until the next flush that changes the framebuffer or until Stop
+func (r *Recorder) FrameEnd(frames uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.recording || r.err != nil || r.pending == nil {
+		return
+	}
+
+	r.now = r.emulated(frames)
+
+	// The video is written as the frames pass, so a long frame is not kept until the next flush
+	if r.format == RecordY4M && r.now > r.frame {
+		r.write(r.now)
+		r.frame = r.now
+	}
+}
+
+// emulated returns frames on the time of recording, that keeps running after a reset of Cpu
+func (r *Recorder) emulated(frames uint64) uint64 {
+	frames += r.offset
+
+	// The frames restart when Cpu is reset, so the recording continues from the next frame
+	if frames < r.frame {
+		r.offset += r.frame + 1 - frames
+		frames = r.frame + 1
+	}
+
+	return frames
+}
+
+// capture makes pending the current frame of framebuffer
+func (r *Recorder) capture() error {
+	r.version = r.framebuffer.Version()
+
+	// Low resolution is scaled to the size of high resolution
+	width := r.framebuffer.Width()
+	if width <= 0 {
+		return fmt.Errorf("%w: width %d", ErrInvalidFramebuffer, width)
+	}
+
+	scale := r.scale * hiResScreenWidth / width
+	pending, err := Image(r.framebuffer, &ConfigImage{Palette: r.palette, Scale: scale})
+	if err != nil {
+		return err
+	}
+	r.pending, r.shown = pending, false
+
+	if r.format != RecordY4M {
+		return nil
+	}
+
+	size := len(r.pending.Pix)
+	if len(r.video) != len("FRAME\n")+3*size {
+		r.video = make([]byte, len("FRAME\n")+3*size)
+		copy(r.video, "FRAME\n")
+	}
+
+	planes := r.video[len("FRAME\n"):]
+	for i, index := range r.pending.Pix {
+		yuv := r.yuv[index]
+		planes[i], planes[size+i], planes[2*size+i] = yuv[0], yuv[1], yuv[2]
+	}
+
+	return nil
+}
+
+// write writes pending lasting until the frame end
+func (r *Recorder) write(end uint64) {
+	if r.format == RecordY4M {
+		for i := r.frame; i < end && r.err == nil; i++ {
+			_, r.err = r.w.Write(r.video)
+			r.shown = true
+		}
+		return
+	}
+
+	// The delays of GIF are in centiseconds, so they are rounded keeping the total time of recording
+	total := int((end - r.start) * 100 / 60)
+	r.gif.Image = append(r.gif.Image, r.pending)
+	r.gif.Delay = append(r.gif.Delay, total-r.delay)
+	r.delay = total
+}
@@ -0,0 +1,91 @@
+package chip8
+
+import "bytes"
+
+// Renderer is how StandardDisplay paints the screen as text, a pixel is set when it is set on any plane
+type Renderer int
+
+const (
+	// RendererBlocks paints each pixel as a character, Black when set and White when not
+	RendererBlocks Renderer = iota
+
+	// RendererHalfBlock paints 2 rows of pixels by character, with the upper and lower half blocks
+	RendererHalfBlock
+
+	// RendererBraille paints 2x4 pixels by character, with the braille patterns
+	RendererBraille
+
+	// RendererASCII paints each pixel as a character, ASCIISet when set and ASCIIUnset when not
+	RendererASCII
+)
+
+// ASCIISet and ASCIIUnset are the characters of pixels on RendererASCII
+const (
+	ASCIISet   = '#'
+	ASCIIUnset = '.'
+)
+
+// The half blocks indexed by the upper pixel on bit 0 and the lower pixel on bit 1
+var halfBlocks = [4]string{" ", "▀", "▄", "█"}
+
+// The bits of braille dots indexed by row and column of pixel on the cell
+var brailleDots = [4][2]rune{{0x01, 0x08}, {0x02, 0x10}, {0x04, 0x20}, {0x40, 0x80}}
+
+const brailleBlank = 0x2800
+
+// render writes width x height pixels of screen on buf, a line of characters by line
+func (r Renderer) render(buf *bytes.Buffer, screen *[hiResScreenHeight][hiResScreenWidth]byte, width, height int) {
+	switch r {
+	case RendererHalfBlock:
+		for i := 0; i < height; i += 2 {
+			for j := 0; j < width; j++ {
+				index := 0
+				if screen[i][j] != 0 {
+					index |= 0x1
+				}
+				if i+1 < height && screen[i+1][j] != 0 {
+					index |= 0x2
+				}
+				buf.WriteString(halfBlocks[index])
+			}
+			buf.WriteByte('\n')
+		}
+	case RendererBraille:
+		for i := 0; i < height; i += 4 {
+			for j := 0; j < width; j += 2 {
+				cell := rune(brailleBlank)
+				for y := 0; y < 4 && i+y < height; y++ {
+					for x := 0; x < 2 && j+x < width; x++ {
+						if screen[i+y][j+x] != 0 {
+							cell |= brailleDots[y][x]
+						}
+					}
+				}
+				buf.WriteRune(cell)
+			}
+			buf.WriteByte('\n')
+		}
+	case RendererASCII:
+		for i := 0; i < height; i++ {
+			for j := 0; j < width; j++ {
+				if screen[i][j] != 0 {
+					buf.WriteByte(ASCIISet)
+				} else {
+					buf.WriteByte(ASCIIUnset)
+				}
+			}
+			buf.WriteByte('\n')
+		}
+	default:
+		for i := 0; i < height; i++ {
+			for j := 0; j < width; j++ {
+				if screen[i][j] != 0 {
+					buf.WriteString(Black)
+				} else {
+					buf.WriteString(White)
+				}
+			}
+			buf.WriteByte('\n')
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package chip8
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+)
+
+const defaultRewindFrames = 600
+const defaultRewindBytes = 4 << 20
+
+// Kinds of snapshots recorded by Rewinder
+const (
+	rewindDelta = 0
+	rewindFull  = 1
+)
+
+// ErrRewindEmpty is returned by Rewind when there is no frame recorded before the current one
+var ErrRewindEmpty = errors.New("no frame to rewind")
+
+// Rewinder records the state of Cpu every frame on a bounded ring buffer and steps it back frame by frame.
+// Only the latest state is kept whole, each older frame is kept as the difference to the next one,
+// compressed with run-length encoding
+type Rewinder struct {
+	cpu       *Cpu
+	maxFrames int
+	maxBytes  int
+	current   []byte
+	deltas    [][]byte
+	first     int
+	count     int
+	size      int
+}
+
+type ConfigRewinder struct {
+	Cpu *Cpu
+
+	// Maximum of frames kept, when zero it is 600 (10 seconds)
+	MaxFrames int
+
+	// Maximum of bytes used by the frames kept, when zero it is 4 MiB
+	MaxBytes int
+}
+
+// NewRewinder is a function that receive a config as param and return a pointer to Rewinder
+func NewRewinder(config *ConfigRewinder) *Rewinder {
+	maxFrames := config.MaxFrames
+	if maxFrames <= 0 {
+		maxFrames = defaultRewindFrames
+	}
+
+	maxBytes := config.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = defaultRewindBytes
+	}
+
+	return &Rewinder{
+		cpu:       config.Cpu,
+		maxFrames: maxFrames,
+		maxBytes:  maxBytes,
+		deltas:    make([][]byte, maxFrames),
+	}
+}
+
+// Record saves the current state of Cpu, it should be called after each frame
+// When the buffer is full the oldest frames are dropped
+func (r *Rewinder) Record() error {
+	buf := &bytes.Buffer{}
+	if err := r.cpu.SaveState(buf); err != nil {
+		return err
+	}
+	state := buf.Bytes()
+
+	if r.current != nil {
+		r.push(encodeRewindDelta(state, r.current))
+	}
+	r.size += len(state) - len(r.current)
+	r.current = state
+
+	for r.count > 0 && (r.count > r.maxFrames || r.size > r.maxBytes) {
+		r.dropOldest()
+	}
+
+	return nil
+}
+
+// Rewind restores Cpu to the frame recorded before the current one
+func (r *Rewinder) Rewind() error {
+	if r.count == 0 {
+		return ErrRewindEmpty
+	}
+
+	last := (r.first + r.count - 1) % len(r.deltas)
+	delta := r.deltas[last]
+	previous := decodeRewindDelta(r.current, delta)
+
+	if err := r.cpu.LoadState(bytes.NewReader(previous)); err != nil {
+		return err
+	}
+
+	r.deltas[last] = nil
+	r.count--
+	r.size += len(previous) - len(r.current) - len(delta)
+	r.current = previous
+
+	return nil
+}
+
+// Frames returns how many frames can be rewound
+func (r *Rewinder) Frames() int {
+	return r.count
+}
+
+// Size returns the bytes used by the frames kept
+func (r *Rewinder) Size() int {
+	return r.size
+}
+
+// Reset drops all frames recorded
+func (r *Rewinder) Reset() {
+	r.current = nil
+	r.deltas = make([][]byte, r.maxFrames)
+	r.first, r.count, r.size = 0, 0, 0
+}
+
+func (r *Rewinder) push(delta []byte) {
+	if r.count == len(r.deltas) {
+		r.dropOldest()
+	}
+
+	r.deltas[(r.first+r.count)%len(r.deltas)] = delta
+	r.count++
+	r.size += len(delta)
+}
+
+func (r *Rewinder) dropOldest() {
+	r.size -= len(r.deltas[r.first])
+	r.deltas[r.first] = nil
+	r.first = (r.first + 1) % len(r.deltas)
+	r.count--
+}
+
+// encodeRewindDelta returns how to get previous from current: the XOR of both encoded as a list of
+// runs, each run is the number of zero bytes followed by the number of literal bytes (both uvarint)
+// and the literal bytes. When the sizes differ previous is kept whole
+func encodeRewindDelta(current, previous []byte) []byte {
+	if len(current) != len(previous) {
+		return append([]byte{rewindFull}, previous...)
+	}
+
+	delta := []byte{rewindDelta}
+	varint := make([]byte, binary.MaxVarintLen64)
+	for i := 0; i < len(current); {
+		zeros := i
+		for i < len(current) && current[i] == previous[i] {
+			i++
+		}
+
+		literals := i
+		for i < len(current) && current[i] != previous[i] {
+			i++
+		}
+
+		delta = append(delta, varint[:binary.PutUvarint(varint, uint64(literals-zeros))]...)
+		delta = append(delta, varint[:binary.PutUvarint(varint, uint64(i-literals))]...)
+		for j := literals; j < i; j++ {
+			delta = append(delta, current[j]^previous[j])
+		}
+	}
+
+	return delta
+}
+
+// decodeRewindDelta returns the previous state from current state and the delta of encodeRewindDelta
+func decodeRewindDelta(current, delta []byte) []byte {
+	if delta[0] == rewindFull {
+		return append([]byte{}, delta[1:]...)
+	}
+
+	previous := append([]byte{}, current...)
+	reader := bytes.NewReader(delta[1:])
+	for i := 0; reader.Len() > 0; {
+		zeros, _ := binary.ReadUvarint(reader)
+		literals, _ := binary.ReadUvarint(reader)
+
+		i += int(zeros)
+		for j := 0; j < int(literals); j++ {
+			b, _ := reader.ReadByte()
+			previous[i] ^= b
+			i++
+		}
+	}
+
+	return previous
+}
@@ -1,8 +1,39 @@
 package chip8
 
+import "math"
+
+const patternSize = 16
+const defaultPitch = 64
+
 type Sound interface {
 	/*
 		Beep should go off a beep when called
 	*/
 	Beep()
 }
+
+// PatternSound is a Sound able to play the audio pattern buffer of XO-CHIP
+type PatternSound interface {
+	Sound
+
+	/*
+		SetPattern should set the 128 samples of 1 bit played while the sound timer is active
+	*/
+	SetPattern(pattern [patternSize]byte)
+
+	/*
+		SetPitch should set the playback rate of pattern, see PatternRate
+	*/
+	SetPitch(pitch byte)
+
+	/*
+		SetPlaying should start playing the pattern when playing is true and stop it otherwise
+		It is called when the sound timer becomes active and when it reaches zero
+	*/
+	SetPlaying(playing bool)
+}
+
+// PatternRate returns the playback rate, in samples per second, of the audio pattern for pitch
+func PatternRate(pitch byte) float64 {
+	return 4000 * math.Pow(2, (float64(pitch)-defaultPitch)/48)
+}
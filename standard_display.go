@@ -1,66 +1,325 @@
 package chip8
 
 import (
+	"bytes"
+	"fmt"
 	"io"
+	"sync"
 )
 
 const White = "□"
 const Black = "■"
 
-// StandardDisplay implements interface Display
+// StandardDisplay implements interface Display, HiResDisplay, PlaneDisplay, ResettableDisplay and Framebuffer
+// It is safe for concurrent use, so the frontend can read the pixels while Cpu draws
 type StandardDisplay struct {
-	output io.Writer
-	screen [32][64]byte
+	mu       sync.RWMutex
+	output   io.Writer
+	renderer Renderer
+	screen   [hiResScreenHeight][hiResScreenWidth]byte
+	hiRes    bool
+	planes   byte
+	version  uint64
+
+	// flushMu guards buf, reused by all flushes to not allocate the screen each time
+	flushMu sync.Mutex
+	buf     bytes.Buffer
 }
 
 type ConfigDisplay struct {
 	Output io.Writer
+
+	// Renderer is how Flush paints the screen on Output, when zero it is RendererBlocks
+	Renderer Renderer
 }
 
 // NewStandardDisplay is a function that receive a config as param and return a pointer to StandardDisplay
 func NewStandardDisplay(config *ConfigDisplay) *StandardDisplay {
-	return &StandardDisplay{output: config.Output}
+	return &StandardDisplay{output: config.Output, renderer: config.Renderer, planes: 0x1}
 }
 
-// Flush is a function that paint the screen with information of attribute "screen"
+// Flush is a function that paint the screen with information of attribute "screen" using the Renderer of config
+// A pixel is painted when it is set on any plane
 func (sd *StandardDisplay) Flush() {
-	buf := ""
-	for i := 0; i < 32; i++ {
-		for j := 0; j < 64; j++ {
-			if sd.screen[i][j] == 1 {
-				buf += Black
-			} else {
-				buf += White
-			}
-		}
-		buf += "\n"
-	}
-	sd.output.Write([]byte(buf))
+	sd.flushMu.Lock()
+	defer sd.flushMu.Unlock()
+
+	sd.mu.RLock()
+	defer sd.mu.RUnlock()
+
+	sd.buf.Reset()
+	sd.renderer.render(&sd.buf, &sd.screen, sd.width(), sd.height())
+	sd.output.Write(sd.buf.Bytes())
 }
 
-// Clear sets all pixels to 0
+// Clear sets all pixels of selected planes to 0
 func (sd *StandardDisplay) Clear() {
-	for i := 0; i < 32; i++ {
-		for j := 0; j < 64; j++ {
-			sd.screen[i][j] = 0
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+
+	sd.version++
+	for i := 0; i < hiResScreenHeight; i++ {
+		for j := 0; j < hiResScreenWidth; j++ {
+			sd.screen[i][j] &^= sd.planes
 		}
 	}
 }
 
-// Draw draws a sprint on position xDisplay and yDisplay
+// Draw draws a sprint on position xDisplay and yDisplay of selected planes
 func (sd *StandardDisplay) Draw(xDisplay, yDisplay, sprite byte) bool {
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+
+	if sprite != 0 && sd.planes != 0 {
+		sd.version++
+	}
+
+	collision := false
+	for _, plane := range []byte{0x1, 0x2} {
+		if sd.planes&plane != 0 {
+			collision = sd.drawPlane(plane, xDisplay, yDisplay, sprite) || collision
+		}
+	}
+
+	return collision
+}
+
+// DrawPlane draws a sprint on position xDisplay and yDisplay only of plane
+func (sd *StandardDisplay) DrawPlane(plane, xDisplay, yDisplay, sprite byte) bool {
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+
+	if sprite != 0 {
+		sd.version++
+	}
+
+	return sd.drawPlane(plane, xDisplay, yDisplay, sprite)
+}
+
+func (sd *StandardDisplay) drawPlane(plane, xDisplay, yDisplay, sprite byte) bool {
 	collision := false
+	width, height := sd.width(), sd.height()
 
 	for bitIdx := 0; bitIdx < 8; bitIdx++ {
-		newPixel := (sprite & (1 << (7 - bitIdx))) >> (7 - bitIdx)
-		oldPixel := sd.screen[yDisplay%32][(xDisplay+byte(bitIdx))%64]
+		if sprite&(1<<(7-bitIdx)) == 0 {
+			continue
+		}
 
-		if newPixel == 1 && oldPixel == 1 {
+		pixel := &sd.screen[int(yDisplay)%height][(int(xDisplay)+bitIdx)%width]
+		if *pixel&plane != 0 {
 			collision = true
 		}
 
-		sd.screen[yDisplay%32][(xDisplay+byte(bitIdx))%64] = newPixel ^ oldPixel
+		*pixel ^= plane
 	}
 
 	return collision
 }
+
+// Reset clears all planes, back to low resolution with only plane 1 selected
+func (sd *StandardDisplay) Reset() {
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+
+	sd.version++
+	sd.screen = [hiResScreenHeight][hiResScreenWidth]byte{}
+	sd.hiRes = false
+	sd.planes = 0x1
+}
+
+// SelectPlanes selects the planes changed by Clear, Draw and the scrolls
+func (sd *StandardDisplay) SelectPlanes(mask byte) {
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+
+	sd.planes = mask & 0x3
+}
+
+// SetHighResolution switches between 64x32 and 128x64, clearing the screen
+func (sd *StandardDisplay) SetHighResolution(enabled bool) {
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+
+	sd.version++
+	sd.hiRes = enabled
+	sd.screen = [hiResScreenHeight][hiResScreenWidth]byte{}
+}
+
+// HighResolution returns true when the display is on 128x64
+func (sd *StandardDisplay) HighResolution() bool {
+	sd.mu.RLock()
+	defer sd.mu.RUnlock()
+
+	return sd.hiRes
+}
+
+// ScrollDown moves all pixels of selected planes n lines down
+func (sd *StandardDisplay) ScrollDown(n byte) {
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+
+	sd.version++
+	for i := sd.height() - 1; i >= 0; i-- {
+		for j := 0; j < sd.width(); j++ {
+			sd.move(i, j, i-int(n), j)
+		}
+	}
+}
+
+// ScrollUp moves all pixels of selected planes n lines up
+func (sd *StandardDisplay) ScrollUp(n byte) {
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+
+	sd.version++
+	for i := 0; i < sd.height(); i++ {
+		for j := 0; j < sd.width(); j++ {
+			sd.move(i, j, i+int(n), j)
+		}
+	}
+}
+
+// ScrollRight moves all pixels of selected planes 4 columns right
+func (sd *StandardDisplay) ScrollRight() {
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+
+	sd.version++
+	for i := 0; i < sd.height(); i++ {
+		for j := sd.width() - 1; j >= 0; j-- {
+			sd.move(i, j, i, j-4)
+		}
+	}
+}
+
+// ScrollLeft moves all pixels of selected planes 4 columns left
+func (sd *StandardDisplay) ScrollLeft() {
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+
+	sd.version++
+	for i := 0; i < sd.height(); i++ {
+		for j := 0; j < sd.width(); j++ {
+			sd.move(i, j, i, j+4)
+		}
+	}
+}
+
+// move copies the selected planes of pixel (fromI, fromJ) to pixel (i, j)
+// When (fromI, fromJ) is out of screen the selected planes of pixel (i, j) are set to 0
+func (sd *StandardDisplay) move(i, j, fromI, fromJ int) {
+	var from byte
+	if fromI >= 0 && fromI < sd.height() && fromJ >= 0 && fromJ < sd.width() {
+		from = sd.screen[fromI][fromJ]
+	}
+
+	sd.screen[i][j] = (sd.screen[i][j] &^ sd.planes) | (from & sd.planes)
+}
+
+// MarshalBinary returns the resolution, selected planes and pixels, implementing encoding.BinaryMarshaler
+func (sd *StandardDisplay) MarshalBinary() ([]byte, error) {
+	sd.mu.RLock()
+	defer sd.mu.RUnlock()
+
+	data := make([]byte, 0, 2+hiResScreenHeight*hiResScreenWidth)
+
+	hiRes := byte(0)
+	if sd.hiRes {
+		hiRes = 1
+	}
+	data = append(data, hiRes, sd.planes)
+
+	for i := 0; i < hiResScreenHeight; i++ {
+		data = append(data, sd.screen[i][:]...)
+	}
+
+	return data, nil
+}
+
+// UnmarshalBinary restores the data of MarshalBinary, implementing encoding.BinaryUnmarshaler
+func (sd *StandardDisplay) UnmarshalBinary(data []byte) error {
+	if len(data) != 2+hiResScreenHeight*hiResScreenWidth {
+		return fmt.Errorf("display of %d bytes, expected %d", len(data), 2+hiResScreenHeight*hiResScreenWidth)
+	}
+	if data[0] > 1 || data[1] > 0x3 {
+		return fmt.Errorf("display of resolution %d and planes 0x%X", data[0], data[1])
+	}
+
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+
+	sd.version++
+	sd.hiRes = data[0] == 1
+	sd.planes = data[1]
+
+	for i := 0; i < hiResScreenHeight; i++ {
+		copy(sd.screen[i][:], data[2+i*hiResScreenWidth:])
+	}
+
+	return nil
+}
+
+// Width returns the width of screen on current resolution
+func (sd *StandardDisplay) Width() int {
+	sd.mu.RLock()
+	defer sd.mu.RUnlock()
+
+	return sd.width()
+}
+
+// Height returns the height of screen on current resolution
+func (sd *StandardDisplay) Height() int {
+	sd.mu.RLock()
+	defer sd.mu.RUnlock()
+
+	return sd.height()
+}
+
+// Pixel returns the planes set on pixel x, y, 0 when it is off or out of screen
+func (sd *StandardDisplay) Pixel(x, y int) byte {
+	sd.mu.RLock()
+	defer sd.mu.RUnlock()
+
+	if x < 0 || y < 0 || x >= sd.width() || y >= sd.height() {
+		return 0
+	}
+
+	return sd.screen[y][x]
+}
+
+// Pixels copies all pixels of screen row by row to dst, reusing its capacity, and returns it
+func (sd *StandardDisplay) Pixels(dst []byte) []byte {
+	sd.mu.RLock()
+	defer sd.mu.RUnlock()
+
+	dst = dst[:0]
+	for i := 0; i < sd.height(); i++ {
+		dst = append(dst, sd.screen[i][:sd.width()]...)
+	}
+
+	return dst
+}
+
+// Version returns a counter incremented on each change of pixels
+func (sd *StandardDisplay) Version() uint64 {
+	sd.mu.RLock()
+	defer sd.mu.RUnlock()
+
+	return sd.version
+}
+
+func (sd *StandardDisplay) width() int {
+	if sd.hiRes {
+		return hiResScreenWidth
+	}
+
+	return screenWidth
+}
+
+func (sd *StandardDisplay) height() int {
+	if sd.hiRes {
+		return hiResScreenHeight
+	}
+
+	return screenHeight
+}
@@ -4,6 +4,7 @@ import "io"
 
 // StandardKeyboard implements KeyBoard
 // It's just useful for example "terminal.go"
+// It has no keypad state, each key read from input is down once, so it is not saved by SaveState
 type StandardKeyboard struct {
 	input io.Reader
 }
@@ -5,10 +5,13 @@ import (
 	"io"
 )
 
-const memSize = 0xFFF
+const memSize = 0x1000
 const romAddressOffset = 0x200
-const romSize = memSize - romAddressOffset
+
+// XOChipMemorySize is the size of memory on XO-CHIP (64 KiB)
+const XOChipMemorySize = 0x10000
 const fontAddressOffset = 0x0
+const bigFontAddressOffset = 0x50
 
 // Sprite of fonts
 var fonts = []byte{
@@ -46,45 +49,144 @@ var fonts = []byte{
 	0xF0, 0x80, 0xF0, 0x80, 0x80,
 }
 
-// StandardMemory implements interface Memory
+// Sprite of big fonts (SUPER-CHIP)
+var bigFonts = []byte{
+	// 0
+	0xFF, 0xFF, 0xC3, 0xC3, 0xC3, 0xC3, 0xC3, 0xC3, 0xFF, 0xFF,
+	// 1
+	0x18, 0x78, 0x78, 0x18, 0x18, 0x18, 0x18, 0x18, 0xFF, 0xFF,
+	// 2
+	0xFF, 0xFF, 0x03, 0x03, 0xFF, 0xFF, 0xC0, 0xC0, 0xFF, 0xFF,
+	// 3
+	0xFF, 0xFF, 0x03, 0x03, 0xFF, 0xFF, 0x03, 0x03, 0xFF, 0xFF,
+	// 4
+	0xC3, 0xC3, 0xC3, 0xC3, 0xFF, 0xFF, 0x03, 0x03, 0x03, 0x03,
+	// 5
+	0xFF, 0xFF, 0xC0, 0xC0, 0xFF, 0xFF, 0x03, 0x03, 0xFF, 0xFF,
+	// 6
+	0xFF, 0xFF, 0xC0, 0xC0, 0xFF, 0xFF, 0xC3, 0xC3, 0xFF, 0xFF,
+	// 7
+	0xFF, 0xFF, 0x03, 0x03, 0x06, 0x0C, 0x18, 0x18, 0x18, 0x18,
+	// 8
+	0xFF, 0xFF, 0xC3, 0xC3, 0xFF, 0xFF, 0xC3, 0xC3, 0xFF, 0xFF,
+	// 9
+	0xFF, 0xFF, 0xC3, 0xC3, 0xFF, 0xFF, 0x03, 0x03, 0xFF, 0xFF,
+	// A
+	0x7E, 0xFF, 0xC3, 0xC3, 0xC3, 0xFF, 0xFF, 0xC3, 0xC3, 0xC3,
+	// B
+	0xFC, 0xFC, 0xC3, 0xC3, 0xFC, 0xFC, 0xC3, 0xC3, 0xFC, 0xFC,
+	// C
+	0x3C, 0xFF, 0xC3, 0xC0, 0xC0, 0xC0, 0xC0, 0xC3, 0xFF, 0x3C,
+	// D
+	0xFC, 0xFE, 0xC3, 0xC3, 0xC3, 0xC3, 0xC3, 0xC3, 0xFE, 0xFC,
+	// E
+	0xFF, 0xFF, 0xC0, 0xC0, 0xFF, 0xFF, 0xC0, 0xC0, 0xFF, 0xFF,
+	// F
+	0xFF, 0xFF, 0xC0, 0xC0, 0xFF, 0xFF, 0xC0, 0xC0, 0xC0, 0xC0,
+}
+
+// StandardMemory implements interface Memory, LargeFontMemory and ResettableMemory
 type StandardMemory struct {
-	mem [memSize]byte
+	mem []byte
+	rom []byte
 	log io.Writer
+	err error
 }
 
 type ConfigMemory struct {
 	Rom io.Reader
 	Log io.Writer
+
+	// Size of memory in bytes, when zero it has 0x1000 bytes (4 KiB)
+	// It must hold at least the fonts and the address of ROM, 0x200 bytes, otherwise the memory has 4 KiB
+	Size int
 }
 
 // NewStandardMemory is a function that receive a config as param and return a pointer to StandardMemory
+// When the size of config is invalid or the ROM can not be loaded, as by LoadROM, the memory is created
+// without the ROM and Err returns the error
 func NewStandardMemory(config *ConfigMemory) *StandardMemory {
+	size := config.Size
+	if size == 0 {
+		size = memSize
+	}
+
 	sm := &StandardMemory{log: config.Log}
-	sm.loadFonts()
-	sm.loadGame(config.Rom)
+	if size < romAddressOffset {
+		sm.err = fmt.Errorf("memory of %d bytes, expected at least %d", size, romAddressOffset)
+		size = memSize
+	}
+	sm.mem = make([]byte, size)
+
+	if sm.err == nil {
+		sm.rom, sm.err = sm.readRom(config.Rom)
+	}
+	sm.Reset()
 
 	return sm
 }
 
+// Err returns the error of NewStandardMemory, nil when the memory was created as configured
+func (sm *StandardMemory) Err() error {
+	return sm.err
+}
+
+// Reset clears the memory, loading again the fonts and the ROM
+func (sm *StandardMemory) Reset() {
+	for i := range sm.mem {
+		sm.mem[i] = 0
+	}
+
+	sm.loadFonts()
+	copy(sm.mem[romAddressOffset:], sm.rom)
+}
+
+// LoadROM replaces the ROM and resets the memory
+// The memory is not changed when the ROM does not fit on it
+func (sm *StandardMemory) LoadROM(rom io.Reader) error {
+	data, err := sm.readRom(rom)
+	if err != nil {
+		return err
+	}
+
+	sm.rom = data
+	sm.Reset()
+	return nil
+}
+
 func (sm *StandardMemory) loadFonts() {
 	for i := 0; i < len(fonts); i++ {
 		sm.mem[fontAddressOffset+i] = fonts[i]
 	}
-}
 
-func (sm *StandardMemory) loadGame(rom io.Reader) {
-	buf := [romSize]byte{}
-	n, err := rom.Read(buf[:])
-	if err != nil {
-		panic(err)
+	for i := 0; i < len(bigFonts); i++ {
+		sm.mem[bigFontAddressOffset+i] = bigFonts[i]
 	}
-	if n == 0 {
-		return
+}
+
+// readRom reads the ROM, that must fit from the address of ROM to the end of memory
+func (sm *StandardMemory) readRom(rom io.Reader) ([]byte, error) {
+	buf := make([]byte, len(sm.mem)-romAddressOffset)
+	total := 0
+	for total < len(buf) {
+		n, err := rom.Read(buf[total:])
+		total += n
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if err == io.EOF || n == 0 {
+			break
+		}
 	}
 
-	for i := 0; i < n; i++ {
-		sm.mem[romAddressOffset+i] = buf[i]
+	if total == len(buf) {
+		var extra [1]byte
+		if n, _ := rom.Read(extra[:]); n > 0 {
+			return nil, fmt.Errorf("rom larger than %d bytes", len(buf))
+		}
 	}
+
+	return buf[:total], nil
 }
 
 // Log writes values of memory to "log" of Memory
@@ -92,6 +194,11 @@ func (sm *StandardMemory) Log() {
 	sm.log.Write([]byte(fmt.Sprintf("memory: %v\n", sm.mem)))
 }
 
+// Size returns the size of memory in bytes
+func (sm *StandardMemory) Size() int {
+	return len(sm.mem)
+}
+
 // SaveBCD convert vx byte to decimal and save each digit on I, I+1 and I+2
 func (sm *StandardMemory) SaveBCD(vx byte, i uint16) {
 	sm.mem[i] = vx / 100
@@ -127,7 +234,35 @@ func (sm *StandardMemory) LoadChar(vx byte) uint16 {
 	return uint16(vx * 5)
 }
 
+// LoadBigChar returns the address to big char VX
+func (sm *StandardMemory) LoadBigChar(vx byte) uint16 {
+	if vx > 0xF {
+		return bigFontAddressOffset
+	}
+
+	return bigFontAddressOffset + uint16(vx)*10
+}
+
 // LoadSprit returns the sprite on position I
 func (sm *StandardMemory) LoadSprite(i uint16) byte {
 	return sm.mem[i]
 }
+
+// MarshalBinary returns a copy of memory, implementing encoding.BinaryMarshaler
+func (sm *StandardMemory) MarshalBinary() ([]byte, error) {
+	data := make([]byte, len(sm.mem))
+	copy(data, sm.mem)
+
+	return data, nil
+}
+
+// UnmarshalBinary replaces the content of memory, implementing encoding.BinaryUnmarshaler
+// The data must have the size of memory
+func (sm *StandardMemory) UnmarshalBinary(data []byte) error {
+	if len(data) != len(sm.mem) {
+		return fmt.Errorf("memory of %d bytes, expected %d", len(data), len(sm.mem))
+	}
+
+	copy(sm.mem, data)
+	return nil
+}
@@ -0,0 +1,270 @@
+package chip8
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+const stateMagic = "CH8S"
+const stateVersion = 1
+
+// Sections of save state
+const (
+	stateSectionCpu      = 'C'
+	stateSectionQuirks   = 'Q'
+	stateSectionMemory   = 'M'
+	stateSectionDisplay  = 'D'
+	stateSectionKeyboard = 'K'
+)
+
+// ErrInvalidState is returned by LoadState when the save state is corrupted or not supported
+var ErrInvalidState = errors.New("invalid save state")
+
+// cpuState is the binary layout of registers of Cpu on save state
+type cpuState struct {
+	Register Register
+	Stack    Stack
+	Flags    Register
+	Pattern  [patternSize]byte
+	PC       uint16
+	I        uint16
+	SP       byte
+	DT       byte
+	ST       byte
+	Pitch    byte
+	Planes   byte
+	Frames   uint64
+	Cycles   uint32
+}
+
+// SaveState writes the state of machine on w: registers, quirks and the devices that implement
+// encoding.BinaryMarshaler (StandardMemory and StandardDisplay do it).
+// The keypad is saved only by a keyboard that implements it, StandardKeyboard does not as it reads
+// the keys from a stream without state. A wait for key of FX0A is kept anyway, as it repeats on PC.
+//
+// The format is the magic "CH8S", the version (uint16), a list of sections and the CRC-32 of all
+// previous bytes (uint32). Each section is a tag (byte), the size of data (uint32) and the data.
+// All numbers are big endian
+func (c *Cpu) SaveState(w io.Writer) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	buf := &bytes.Buffer{}
+	buf.WriteString(stateMagic)
+	binary.Write(buf, binary.BigEndian, uint16(stateVersion))
+
+	cpu := &bytes.Buffer{}
+	binary.Write(cpu, binary.BigEndian, c.cpuState())
+	writeStateSection(buf, stateSectionCpu, cpu.Bytes())
+
+	quirks := &bytes.Buffer{}
+	binary.Write(quirks, binary.BigEndian, c.quirks)
+	writeStateSection(buf, stateSectionQuirks, quirks.Bytes())
+
+	devices := []struct {
+		tag    byte
+		device interface{}
+	}{
+		{stateSectionMemory, c.memory},
+		{stateSectionDisplay, c.display},
+		{stateSectionKeyboard, c.keyboard},
+	}
+	for _, d := range devices {
+		marshaler, ok := d.device.(encoding.BinaryMarshaler)
+		if !ok {
+			continue
+		}
+
+		data, err := marshaler.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		writeStateSection(buf, d.tag, data)
+	}
+
+	binary.Write(buf, binary.BigEndian, crc32.ChecksumIEEE(buf.Bytes()))
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// LoadState restores the state of machine written by SaveState
+// The devices with state saved must implement encoding.BinaryUnmarshaler
+// The whole state is validated before being applied, so a state not loaded leaves the machine unchanged
+// After loading, Cpu is no longer halted
+func (c *Cpu) LoadState(r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	sections, err := readStateSections(data)
+	if err != nil {
+		return err
+	}
+
+	cpu, quirks := cpuState{}, Quirks{}
+	if err := readStateStruct(sections[stateSectionCpu], &cpu); err != nil {
+		return err
+	}
+	if err := readStateStruct(sections[stateSectionQuirks], &quirks); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.validateState(cpu.PC, cpu.I, cpu.SP, cpu.Stack, cpu.Planes); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidState, err.Error())
+	}
+
+	// The devices are checked before changing any of them, keeping a backup to restore them when one fails
+	type deviceState struct {
+		unmarshaler encoding.BinaryUnmarshaler
+		section     []byte
+		backup      []byte
+	}
+
+	var devices []deviceState
+	for _, d := range []struct {
+		tag    byte
+		device interface{}
+	}{
+		{stateSectionMemory, c.memory},
+		{stateSectionDisplay, c.display},
+		{stateSectionKeyboard, c.keyboard},
+	} {
+		section, ok := sections[d.tag]
+		if !ok {
+			continue
+		}
+
+		unmarshaler, ok := d.device.(encoding.BinaryUnmarshaler)
+		if !ok {
+			return fmt.Errorf("%w: device of section %q does not implement encoding.BinaryUnmarshaler", ErrInvalidState, d.tag)
+		}
+
+		marshaler, ok := d.device.(encoding.BinaryMarshaler)
+		if !ok {
+			return fmt.Errorf("%w: device of section %q does not implement encoding.BinaryMarshaler", ErrInvalidState, d.tag)
+		}
+
+		backup, err := marshaler.MarshalBinary()
+		if err != nil {
+			return err
+		}
+
+		devices = append(devices, deviceState{unmarshaler: unmarshaler, section: section, backup: backup})
+	}
+
+	for i, d := range devices {
+		if err := d.unmarshaler.UnmarshalBinary(d.section); err != nil {
+			for _, applied := range devices[:i] {
+				applied.unmarshaler.UnmarshalBinary(applied.backup)
+			}
+
+			return fmt.Errorf("%w: %s", ErrInvalidState, err.Error())
+		}
+	}
+
+	c.setCpuState(cpu)
+	c.quirks = quirks
+	c.halted = false
+	c.err = nil
+
+	return nil
+}
+
+func (c *Cpu) cpuState() cpuState {
+	return cpuState{
+		Register: c.register,
+		Stack:    c.stack,
+		Flags:    c.flags,
+		Pattern:  c.pattern,
+		PC:       c.pc,
+		I:        c.i,
+		SP:       c.sp,
+		DT:       c.dt,
+		ST:       c.st,
+		Pitch:    c.pitch,
+		Planes:   c.planes,
+		Frames:   c.frames,
+		Cycles:   uint32(c.cycles),
+	}
+}
+
+func (c *Cpu) setCpuState(state cpuState) {
+	c.register = state.Register
+	c.stack = state.Stack
+	c.flags = state.Flags
+	c.pattern = state.Pattern
+	c.pc = state.PC
+	c.i = state.I
+	c.sp = state.SP
+	c.dt = state.DT
+	c.st = state.ST
+	c.pitch = state.Pitch
+	c.planes = state.Planes
+	c.frames = state.Frames
+	c.cycles = int(state.Cycles)
+
+	if sound, ok := c.sound.(PatternSound); ok {
+		sound.SetPattern(c.pattern)
+		sound.SetPitch(c.pitch)
+		sound.SetPlaying(c.st > 0)
+	}
+}
+
+func writeStateSection(buf *bytes.Buffer, tag byte, data []byte) {
+	buf.WriteByte(tag)
+	binary.Write(buf, binary.BigEndian, uint32(len(data)))
+	buf.Write(data)
+}
+
+// readStateSections validates magic, version and checksum of data, returning its sections by tag
+func readStateSections(data []byte) (map[byte][]byte, error) {
+	header := len(stateMagic) + 2
+	if len(data) < header+4 || string(data[:len(stateMagic)]) != stateMagic {
+		return nil, fmt.Errorf("%w: not a save state", ErrInvalidState)
+	}
+
+	body, checksum := data[:len(data)-4], binary.BigEndian.Uint32(data[len(data)-4:])
+	if crc32.ChecksumIEEE(body) != checksum {
+		return nil, fmt.Errorf("%w: checksum mismatch", ErrInvalidState)
+	}
+
+	if version := binary.BigEndian.Uint16(data[len(stateMagic):header]); version != stateVersion {
+		return nil, fmt.Errorf("%w: version %d not supported", ErrInvalidState, version)
+	}
+
+	sections := map[byte][]byte{}
+	for offset := header; offset < len(body); {
+		if offset+5 > len(body) {
+			return nil, fmt.Errorf("%w: truncated section", ErrInvalidState)
+		}
+
+		tag, size := body[offset], int(binary.BigEndian.Uint32(body[offset+1:offset+5]))
+		offset += 5
+		if offset+size > len(body) {
+			return nil, fmt.Errorf("%w: truncated section %q", ErrInvalidState, tag)
+		}
+
+		sections[tag] = body[offset : offset+size]
+		offset += size
+	}
+
+	return sections, nil
+}
+
+func readStateStruct(section []byte, data interface{}) error {
+	if len(section) != binary.Size(data) {
+		return fmt.Errorf("%w: section of size %d, expected %d", ErrInvalidState, len(section), binary.Size(data))
+	}
+
+	return binary.Read(bytes.NewReader(section), binary.BigEndian, data)
+}
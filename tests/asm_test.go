@@ -0,0 +1,188 @@
+package chip8_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	chip8 "github.com/MarceloMPJR/go-chip-8"
+	"github.com/MarceloMPJR/go-chip-8/asm"
+	"github.com/MarceloMPJR/go-chip-8/disasm"
+)
+
+func assemble(t *testing.T, src string, fs fstest.MapFS) *asm.Program {
+	t.Helper()
+
+	assembler := asm.NewAssembler(&asm.ConfigAssembler{FS: fs})
+	program, err := assembler.Assemble("main.asm", strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("error not expected: %s", err.Error())
+	}
+
+	return program
+}
+
+func TestAssembler_RoundTrip(t *testing.T) {
+	t.Run("when the source is the disassembly of a ROM", func(t *testing.T) {
+		rom, err := os.ReadFile(filepath.Join("testdata", "counter.ch8"))
+		if err != nil {
+			t.Fatalf("error not expected: %s", err.Error())
+		}
+
+		src := &bytes.Buffer{}
+		disasm.NewDisassembler(&disasm.ConfigDisassembler{Comments: true}).Write(src, rom)
+
+		if result := assemble(t, src.String(), nil).Rom; !bytes.Equal(result, rom) {
+			t.Errorf("result: % X, expected: % X", result, rom)
+		}
+	})
+
+	t.Run("when the source is the string of all instructions", func(t *testing.T) {
+		// Each type of instruction is assembled apart to fit on memory
+		for instrType := 0x0; instrType <= 0xF; instrType++ {
+			src := &strings.Builder{}
+			rom := []byte{}
+			for op := instrType << 12; op < (instrType+1)<<12; op++ {
+				if op == 0xF000 {
+					// The address of LD I, LONG is the word that follows it
+					continue
+				}
+
+				instr := chip8.Instruction{byte(op >> 8), byte(op)}
+				fmt.Fprintln(src, instr.String())
+				rom = append(rom, instr...)
+			}
+
+			result := assemble(t, src.String(), nil).Rom
+			for idx := 0; idx < len(rom); idx += 2 {
+				if !bytes.Equal(result[idx:idx+2], rom[idx:idx+2]) {
+					t.Fatalf("result: % X, expected: % X", result[idx:idx+2], rom[idx:idx+2])
+				}
+			}
+		}
+	})
+}
+
+func TestAssembler_Assemble(t *testing.T) {
+	src := `
+; constants may be used before they are defined
+X EQU WIDTH / 2 - 4
+WIDTH EQU 64
+
+start:	CLS
+	ld v0, X
+	LD V1, -1
+	LD I, sprite
+	DRW V0, V1, end - sprite
+	shr va
+	LD I, LONG far
+	JP V0, (start + 2) & 0xFFF
+loop:	JP loop
+sprite:
+	DB 0b11110000, 0x90, "OK"
+	DW 0x1234, sprite
+end:
+	include "lib/far.asm"
+`
+	fs := fstest.MapFS{
+		"lib/far.asm":  {Data: []byte("far: db 1\n include \"data.asm\"\n")},
+		"lib/data.asm": {Data: []byte("data: dw far\n")},
+	}
+
+	program := assemble(t, src, fs)
+
+	expected := []byte{
+		0x00, 0xE0, // CLS
+		0x60, 0x1C, // LD V0, 28
+		0x61, 0xFF, // LD V1, -1
+		0xA2, 0x14, // LD I, sprite
+		0xD0, 0x18, // DRW V0, V1, 8
+		0x8A, 0xA6, // SHR VA
+		0xF0, 0x00, 0x02, 0x1C, // LD I, LONG far
+		0xB2, 0x02, // JP V0, 0x202
+		0x12, 0x12, // JP loop
+		0xF0, 0x90, 'O', 'K', // sprite
+		0x12, 0x34, 0x02, 0x14,
+		0x01,       // far
+		0x02, 0x1C, // data
+	}
+
+	if !bytes.Equal(program.Rom, expected) {
+		t.Errorf("result: % X, expected: % X", program.Rom, expected)
+	}
+
+	symbols := map[string]uint16{"start": 0x200, "loop": 0x212, "sprite": 0x214, "end": 0x21C, "far": 0x21C, "data": 0x21D}
+	for name, addr := range symbols {
+		if result, ok := program.Symbols[name]; !ok || result != addr {
+			t.Errorf("symbol %s result: 0x%03X, expected: 0x%03X", name, result, addr)
+		}
+	}
+
+	t.Run("when the origin is other address", func(t *testing.T) {
+		assembler := asm.NewAssembler(&asm.ConfigAssembler{Origin: 0x600})
+		program, err := assembler.Assemble("", strings.NewReader("here: JP here"))
+		if err != nil {
+			t.Fatalf("error not expected: %s", err.Error())
+		}
+
+		if expected := []byte{0x16, 0x00}; !bytes.Equal(program.Rom, expected) {
+			t.Errorf("result: % X, expected: % X", program.Rom, expected)
+		}
+	})
+}
+
+func TestAssembler_Errors(t *testing.T) {
+	testCases := []struct {
+		src      string
+		expected string
+	}{
+		{"FOO V0", "main.asm:1:1: unknown instruction FOO"},
+		{"\tLD V0, K, 1", "main.asm:1:2: invalid operands of LD"},
+		{"\tLD V0, 0x100", "main.asm:1:9: value 256 out of range -128..255"},
+		{"\tJP missing", "main.asm:1:5: undefined symbol missing"},
+		{"CLS\n\tLD V0, 1 +", "main.asm:2:12: expression expected"},
+		{"\tLD V0, (1 + 2", "main.asm:1:15: missing )"},
+		{"\tLD V0, 1 / 0", "main.asm:1:11: division by zero"},
+		{"\tDB 1 2", "main.asm:1:7: unexpected 2"},
+		{"\tDB \"text", "main.asm:1:5: string not terminated"},
+		{"\tLD V0, @", "main.asm:1:9: unexpected character '@'"},
+		{"a: CLS\na: CLS", "main.asm:2:1: a already defined"},
+		{"VA: CLS", "main.asm:1:1: VA is reserved"},
+		{"P EQU Q\nQ EQU P\n\tLD V0, P", "main.asm:2:7: constant P refers to itself"},
+		{"\tDRW V0, V1, 16", "main.asm:1:14: value 16 out of range 0..15"},
+		{"\tLD V0,, 1", "main.asm:1:8: operand expected"},
+		{"include \"lib.asm\"", "lib.asm:2:1: unknown instruction BAD"},
+		{"include \"loop.asm\"", "loop.asm:1:1: include cycle on loop.asm"},
+		{"include \"missing.asm\"", "main.asm:1:1: open missing.asm: file does not exist"},
+	}
+
+	fs := fstest.MapFS{
+		"lib.asm":  {Data: []byte("CLS\nBAD V0\n")},
+		"loop.asm": {Data: []byte("include \"loop.asm\"\n")},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.expected, func(t *testing.T) {
+			assembler := asm.NewAssembler(&asm.ConfigAssembler{FS: fs})
+			_, err := assembler.Assemble("main.asm", strings.NewReader(tC.src))
+			if err == nil {
+				t.Fatalf("error expected: %s", tC.expected)
+			}
+
+			if err.Error() != tC.expected {
+				t.Errorf("result: %s, expected: %s", err.Error(), tC.expected)
+			}
+		})
+	}
+
+	t.Run("when include is not allowed", func(t *testing.T) {
+		_, err := asm.NewAssembler(&asm.ConfigAssembler{}).Assemble("main.asm", strings.NewReader("include \"a.asm\""))
+		if expected := "main.asm:1:1: include is not allowed"; err == nil || err.Error() != expected {
+			t.Errorf("result: %v, expected: %s", err, expected)
+		}
+	})
+}
@@ -0,0 +1,34 @@
+package chip8_test
+
+import (
+	"testing"
+	"time"
+
+	chip8 "github.com/MarceloMPJR/go-chip-8"
+)
+
+func TestVirtualClock_Sleep(t *testing.T) {
+	clock := chip8.NewVirtualClock()
+
+	start := time.Now()
+	clock.Sleep(time.Hour)
+	clock.Sleep(time.Minute)
+
+	if clock.Now() != time.Hour+time.Minute {
+		t.Errorf("result: %v, expected: %v", clock.Now(), time.Hour+time.Minute)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Sleep blocked for %v", elapsed)
+	}
+}
+
+func TestRealClock_Sleep(t *testing.T) {
+	clock := chip8.NewRealClock()
+
+	clock.Sleep(20 * time.Millisecond)
+
+	if clock.Now() < 20*time.Millisecond {
+		t.Errorf("result: %v, expected at least: %v", clock.Now(), 20*time.Millisecond)
+	}
+}
@@ -2,9 +2,15 @@ package chip8_test
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"math/rand"
+	"reflect"
+	"runtime"
 	"testing"
+	"time"
 
 	chip8 "github.com/MarceloMPJR/go-chip-8"
 )
@@ -52,6 +58,8 @@ type cpuTestCaseContext struct {
 	register         chip8.Register
 	stack            chip8.Stack
 	keyPressed       chip8.Key
+	random           chip8.Random
+	quirks           chip8.Quirks
 	sp               byte
 	expectedRegister chip8.Register
 	expectedStack    chip8.Stack
@@ -249,6 +257,13 @@ func TestCpu_Process(t *testing.T) {
 					expectedRegister: chip8.Register{0xAB, 0x0F, 0xAB},
 					pcExpected:       0x2,
 				},
+				{
+					context:          "when quirk VFReset is enabled",
+					quirks:           chip8.Quirks{VFReset: true},
+					register:         chip8.Register{0xAB, 0x0F, 0xCD, 0xF: 0x01},
+					expectedRegister: chip8.Register{0xEF, 0x0F, 0xCD},
+					pcExpected:       0x2,
+				},
 			},
 		},
 		{
@@ -267,6 +282,13 @@ func TestCpu_Process(t *testing.T) {
 					expectedRegister: chip8.Register{0xAB, 0x0F, 0xAB},
 					pcExpected:       0x2,
 				},
+				{
+					context:          "when quirk VFReset is enabled",
+					quirks:           chip8.Quirks{VFReset: true},
+					register:         chip8.Register{0xAB, 0x0F, 0xCD, 0xF: 0x01},
+					expectedRegister: chip8.Register{0x89, 0x0F, 0xCD},
+					pcExpected:       0x2,
+				},
 			},
 		},
 		{
@@ -285,6 +307,13 @@ func TestCpu_Process(t *testing.T) {
 					expectedRegister: chip8.Register{0x0, 0x0F, 0xAB},
 					pcExpected:       0x2,
 				},
+				{
+					context:          "when quirk VFReset is enabled",
+					quirks:           chip8.Quirks{VFReset: true},
+					register:         chip8.Register{0xAB, 0x0F, 0xCD, 0xF: 0x01},
+					expectedRegister: chip8.Register{0x66, 0x0F, 0xCD},
+					pcExpected:       0x2,
+				},
 			},
 		},
 		{
@@ -342,6 +371,14 @@ func TestCpu_Process(t *testing.T) {
 					flag:             true,
 					pcExpected:       0x2,
 				},
+				{
+					context:          "when quirk ShiftUsesVY is enabled",
+					quirks:           chip8.Quirks{ShiftUsesVY: true},
+					register:         chip8.Register{0xAE, 0x03},
+					expectedRegister: chip8.Register{0x01, 0x03},
+					flag:             true,
+					pcExpected:       0x2,
+				},
 			},
 		},
 		{
@@ -380,6 +417,14 @@ func TestCpu_Process(t *testing.T) {
 					flag:             true,
 					pcExpected:       0x2,
 				},
+				{
+					context:          "when quirk ShiftUsesVY is enabled",
+					quirks:           chip8.Quirks{ShiftUsesVY: true},
+					register:         chip8.Register{0x1E, 0x83},
+					expectedRegister: chip8.Register{0x06, 0x83},
+					flag:             true,
+					pcExpected:       0x2,
+				},
 			},
 		},
 		{
@@ -423,6 +468,13 @@ func TestCpu_Process(t *testing.T) {
 					expectedRegister: chip8.Register{0x4A, 0xAB, 0xCD},
 					pcExpected:       0xB06,
 				},
+				{
+					context:          "when quirk JumpUsesVX is enabled",
+					quirks:           chip8.Quirks{JumpUsesVX: true},
+					register:         chip8.Register{0x4A, 0xAB, 0xCD, 0xA: 0x10},
+					expectedRegister: chip8.Register{0x4A, 0xAB, 0xCD, 0xA: 0x10},
+					pcExpected:       0xACC,
+				},
 			},
 		},
 		{
@@ -430,11 +482,19 @@ func TestCpu_Process(t *testing.T) {
 			instr:    chip8.Instruction{0xC0, 0x5E},
 			contexts: []cpuTestCaseContext{
 				{
-					context:          "when random number is 0x0E",
+					context:          "when random number is 0x2F",
+					random:           chip8.NewSequenceRandom(0x2F),
 					register:         chip8.Register{0xFA, 0xBB},
 					expectedRegister: chip8.Register{0x0E, 0xBB},
 					pcExpected:       0x2,
 				},
+				{
+					context:          "when random number is 0xFF",
+					random:           chip8.NewSequenceRandom(0xFF),
+					register:         chip8.Register{0xFA, 0xBB},
+					expectedRegister: chip8.Register{0x5E, 0xBB},
+					pcExpected:       0x2,
+				},
 			},
 		},
 		{
@@ -515,6 +575,13 @@ func TestCpu_Process(t *testing.T) {
 					pcExpected:       0x2,
 					dtExpected:       0x0,
 				},
+				{
+					context:          "when none key is pressed",
+					register:         chip8.Register{0xFA, 0xBB},
+					expectedRegister: chip8.Register{0xFA, 0xBB},
+					keyPressed:       0xFF,
+					pcExpected:       0x0,
+				},
 			},
 		},
 		{
@@ -594,6 +661,15 @@ func TestCpu_Process(t *testing.T) {
 					pcExpected:       0x2,
 					saveCount:        1,
 				},
+				{
+					context:          "when quirk LoadStoreIncrementsI is enabled",
+					quirks:           chip8.Quirks{LoadStoreIncrementsI: true},
+					register:         chip8.Register{0xFA, 0xBB},
+					expectedRegister: chip8.Register{0xFA, 0xBB},
+					pcExpected:       0x2,
+					iExpected:        0x1,
+					saveCount:        1,
+				},
 			},
 		},
 		{
@@ -607,12 +683,19 @@ func TestCpu_Process(t *testing.T) {
 					pcExpected:       0x2,
 					loadCount:        1,
 				},
+				{
+					context:          "when quirk LoadStoreIncrementsI is enabled",
+					quirks:           chip8.Quirks{LoadStoreIncrementsI: true},
+					register:         chip8.Register{0xFA, 0xBB},
+					expectedRegister: chip8.Register{0xFA, 0xBB},
+					pcExpected:       0x2,
+					iExpected:        0x1,
+					loadCount:        1,
+				},
 			},
 		},
 	}
 
-	rand.Seed(51153153)
-
 	// Set register 0xF when flag is true
 	setFlags(tests)
 
@@ -629,8 +712,10 @@ func TestCpu_Process(t *testing.T) {
 						Display:  &display,
 						Keyboard: keyboard,
 						Memory:   &memory,
+						Random:   context.random,
 						Stack:    context.stack,
 						Log:      log,
+						Quirks:   context.quirks,
 						Register: context.register,
 						I:        0x0,
 						PC:       0x0,
@@ -662,6 +747,760 @@ func TestCpu_Process(t *testing.T) {
 	}
 }
 
+func TestCpu_ProcessClipping(t *testing.T) {
+	tests := []struct {
+		context       string
+		quirks        chip8.Quirks
+		expectedDraws []MockDraw
+	}{
+		{
+			context: "when quirk Clipping is disabled",
+			quirks:  chip8.Quirks{},
+			expectedDraws: []MockDraw{
+				{xDisplay: 60, yDisplay: 30, sprite: 0xFF},
+				{xDisplay: 60, yDisplay: 31, sprite: 0xFF},
+				{xDisplay: 60, yDisplay: 32, sprite: 0xFF},
+			},
+		},
+		{
+			context: "when quirk Clipping is enabled",
+			quirks:  chip8.Quirks{Clipping: true},
+			expectedDraws: []MockDraw{
+				{xDisplay: 60, yDisplay: 30, sprite: 0xF0},
+				{xDisplay: 60, yDisplay: 31, sprite: 0xF0},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.context, func(t *testing.T) {
+			display := MockDisplay{}
+			memory := MockMemory{sprite: 0xFF}
+
+			cpu := chip8.NewCpu(&chip8.ConfigCpu{
+				Display:  &display,
+				Memory:   &memory,
+				Quirks:   test.quirks,
+				Register: chip8.Register{60, 94},
+			})
+
+			err := cpu.Process(chip8.Instruction{0xD0, 0x13})
+			if err != nil {
+				t.Fatalf("error not expected: %s", err.Error())
+			}
+
+			if !reflect.DeepEqual(display.draws, test.expectedDraws) {
+				t.Errorf("result: %v, expected: %v", display.draws, test.expectedDraws)
+			}
+		})
+	}
+}
+
+func TestCpu_ProcessSuperChip(t *testing.T) {
+	tests := []struct {
+		describe                 string
+		instr                    chip8.Instruction
+		register                 chip8.Register
+		hiRes                    bool
+		hiResExpected            bool
+		pcExpected               uint16
+		iExpected                uint16
+		scrollDownExpected       byte
+		scrollUpExpected         byte
+		scrollRightCountExpected int
+		scrollLeftCountExpected  int
+		expectedDraws            []MockDraw
+	}{
+		{
+			describe:           "instruction 0x00CN",
+			instr:              chip8.Instruction{0x00, 0xC3},
+			pcExpected:         0x2,
+			scrollDownExpected: 3,
+		},
+		{
+			describe:         "instruction 0x00DN",
+			instr:            chip8.Instruction{0x00, 0xD2},
+			pcExpected:       0x2,
+			scrollUpExpected: 2,
+		},
+		{
+			describe:                 "instruction 0x00FB",
+			instr:                    chip8.Instruction{0x00, 0xFB},
+			pcExpected:               0x2,
+			scrollRightCountExpected: 1,
+		},
+		{
+			describe:                "instruction 0x00FC",
+			instr:                   chip8.Instruction{0x00, 0xFC},
+			pcExpected:              0x2,
+			scrollLeftCountExpected: 1,
+		},
+		{
+			describe:      "instruction 0x00FE",
+			instr:         chip8.Instruction{0x00, 0xFE},
+			hiRes:         true,
+			hiResExpected: false,
+			pcExpected:    0x2,
+		},
+		{
+			describe:      "instruction 0x00FF",
+			instr:         chip8.Instruction{0x00, 0xFF},
+			hiResExpected: true,
+			pcExpected:    0x2,
+		},
+		{
+			describe:      "instruction 0xDXY0",
+			instr:         chip8.Instruction{0xD0, 0x10},
+			register:      chip8.Register{120, 62},
+			hiRes:         true,
+			hiResExpected: true,
+			pcExpected:    0x2,
+			expectedDraws: []MockDraw{
+				{xDisplay: 120, yDisplay: 62, sprite: 0xAA},
+				{xDisplay: 120, yDisplay: 63, sprite: 0xAA},
+			},
+		},
+		{
+			describe:   "instruction 0xFX30",
+			instr:      chip8.Instruction{0xF1, 0x30},
+			register:   chip8.Register{0x00, 0x03},
+			pcExpected: 0x2,
+			iExpected:  0x53,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.describe, func(t *testing.T) {
+			display := MockHiResDisplay{hiRes: test.hiRes}
+			memory := MockMemory{sprite: 0xAA}
+			log := &bytes.Buffer{}
+
+			cpu := chip8.NewCpu(&chip8.ConfigCpu{
+				Display:  &display,
+				Memory:   &memory,
+				Log:      log,
+				Quirks:   chip8.QuirksSCHIP,
+				Register: test.register,
+			})
+
+			err := cpu.Process(test.instr)
+			if err != nil {
+				t.Fatalf("error not expected: %s", err.Error())
+			}
+
+			cpu.Log()
+
+			result := log.Bytes()
+			expected := cpuToStr(test.pcExpected, test.iExpected, test.register, chip8.Stack{}, 0x0, 0x0, 0x0)
+			if string(result) != string(expected) {
+				t.Errorf("result: %s, expected: %s", result, expected)
+			}
+
+			if display.hiRes != test.hiResExpected {
+				t.Errorf("[display hiRes] result: %v, expected: %v", display.hiRes, test.hiResExpected)
+			}
+
+			if display.scrollDown != test.scrollDownExpected {
+				t.Errorf("[display scrollDown] result: %d, expected: %d", display.scrollDown, test.scrollDownExpected)
+			}
+
+			if display.scrollUp != test.scrollUpExpected {
+				t.Errorf("[display scrollUp] result: %d, expected: %d", display.scrollUp, test.scrollUpExpected)
+			}
+
+			if display.scrollRightCount != test.scrollRightCountExpected {
+				t.Errorf("[display scrollRightCount] result: %d, expected: %d", display.scrollRightCount, test.scrollRightCountExpected)
+			}
+
+			if display.scrollLeftCount != test.scrollLeftCountExpected {
+				t.Errorf("[display scrollLeftCount] result: %d, expected: %d", display.scrollLeftCount, test.scrollLeftCountExpected)
+			}
+
+			if !reflect.DeepEqual(display.draws, test.expectedDraws) {
+				t.Errorf("[display draws] result: %v, expected: %v", display.draws, test.expectedDraws)
+			}
+		})
+	}
+}
+
+func TestCpu_ProcessUserFlags(t *testing.T) {
+	log := &bytes.Buffer{}
+	cpu := chip8.NewCpu(&chip8.ConfigCpu{
+		Log:      log,
+		Register: chip8.Register{0x01, 0x02, 0x03, 0x04},
+	})
+
+	instructions := []chip8.Instruction{
+		// Save V0..V2 on flags
+		{0xF2, 0x75},
+		// Clear V0..V2
+		{0x60, 0x00},
+		{0x61, 0x00},
+		{0x62, 0x00},
+		// Load V0..V1 from flags
+		{0xF1, 0x85},
+	}
+
+	for _, instr := range instructions {
+		if err := cpu.Process(instr); err != nil {
+			t.Fatalf("error not expected: %s", err.Error())
+		}
+	}
+
+	cpu.Log()
+
+	result := log.Bytes()
+	expected := cpuToStr(0xA, 0x0, chip8.Register{0x01, 0x02, 0x00, 0x04}, chip8.Stack{}, 0x0, 0x0, 0x0)
+	if string(result) != string(expected) {
+		t.Errorf("result: %s, expected: %s", result, expected)
+	}
+}
+
+func TestCpu_ProcessFlagOnVF(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		instr    chip8.Instruction
+		vf       byte
+		expected byte
+	}{
+		{desc: "when 8FY4 carries", instr: chip8.Instruction{0x8F, 0x14}, vf: 0xFF, expected: 0x01},
+		{desc: "when 8FY5 does not borrow", instr: chip8.Instruction{0x8F, 0x15}, vf: 0x05, expected: 0x01},
+		{desc: "when 8FY5 borrows", instr: chip8.Instruction{0x8F, 0x15}, vf: 0x01, expected: 0x00},
+		{desc: "when 8FY6 shifts out 1", instr: chip8.Instruction{0x8F, 0x16}, vf: 0x03, expected: 0x01},
+		{desc: "when 8FY7 borrows", instr: chip8.Instruction{0x8F, 0x17}, vf: 0x05, expected: 0x00},
+		{desc: "when 8FYE shifts out 1", instr: chip8.Instruction{0x8F, 0x1E}, vf: 0x80, expected: 0x01},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			register := chip8.Register{}
+			register[0x1] = 0x02
+			register[0xF] = tC.vf
+			cpu := chip8.NewCpu(&chip8.ConfigCpu{Register: register})
+
+			if err := cpu.Process(tC.instr); err != nil {
+				t.Fatalf("error not expected: %s", err.Error())
+			}
+
+			// The flag is written after the result
+			if result := cpu.State().Register[0xF]; result != tC.expected {
+				t.Errorf("result: 0x%X, expected: 0x%X", result, tC.expected)
+			}
+		})
+	}
+}
+
+func TestCpu_StartExit(t *testing.T) {
+	memory := chip8.NewStandardMemory(&chip8.ConfigMemory{Rom: bytes.NewReader([]byte{0x60, 0x01, 0x00, 0xFD})})
+	log := &bytes.Buffer{}
+
+	cpu := chip8.NewCpu(&chip8.ConfigCpu{
+		Memory: memory,
+		Sound:  &MockSound{},
+		Log:    log,
+		PC:     0x200,
+	})
+
+	// Start returns when instruction 0x00FD is executed
+	if err := cpu.Start(context.Background()); err != nil {
+		t.Fatalf("error not expected: %s", err.Error())
+	}
+
+	if !cpu.Halted() {
+		t.Errorf("expected halted, but it is running")
+	}
+
+	if cpu.NextInstruction() != 0x202 {
+		t.Errorf("result: 0x%X, expected: 0x%X", cpu.NextInstruction(), 0x202)
+	}
+}
+
+func TestCpu_ProcessXOChip(t *testing.T) {
+	rom := make([]byte, 0x102)
+	copy(rom, []byte{
+		// I := 0x1234
+		0xF0, 0x00, 0x12, 0x34,
+		// V0 := 1, V1 := 2, V2 := 3
+		0x60, 0x01, 0x61, 0x02, 0x62, 0x03,
+		// Save V0..V2
+		0x50, 0x22,
+		// V0 := 0, V1 := 0, V2 := 0
+		0x60, 0x00, 0x61, 0x00, 0x62, 0x00,
+		// Load V2..V0
+		0x52, 0x03,
+		// Skip if V0 == 3, jumping over F000 NNNN
+		0x30, 0x03, 0xF0, 0x00, 0x00, 0x00,
+		// Pitch := V2
+		0xF2, 0x3A,
+		// Select planes 1 and 2
+		0xF3, 0x01,
+		// Audio pattern := [I]
+		0xF0, 0x02,
+		// I := 0x300, draw 1 line on (V0, V1) of each plane
+		0xA3, 0x00, 0xD0, 0x11,
+	})
+	copy(rom[0x100:], []byte{0xAA, 0x55})
+
+	log := &bytes.Buffer{}
+	memory := chip8.NewStandardMemory(&chip8.ConfigMemory{Rom: bytes.NewReader(rom), Size: chip8.XOChipMemorySize})
+	display := MockPlaneDisplay{}
+	sound := MockPatternSound{}
+
+	cpu := chip8.NewCpu(&chip8.ConfigCpu{
+		Display: &display,
+		Memory:  memory,
+		Sound:   &sound,
+		Log:     log,
+		Quirks:  chip8.QuirksXOCHIP,
+		PC:      0x200,
+	})
+
+	for i := 0; i < 15; i++ {
+		if err := cpu.Process(memory.LoadInstruction(cpu.NextInstruction())); err != nil {
+			t.Fatalf("error not expected: %s", err.Error())
+		}
+	}
+
+	cpu.Log()
+
+	result := log.Bytes()
+	expected := cpuToStr(0x224, 0x300, chip8.Register{0x03, 0x02, 0x01}, chip8.Stack{}, 0x0, 0x0, 0x0)
+	if string(result) != string(expected) {
+		t.Errorf("result: %s, expected: %s", result, expected)
+	}
+
+	saved := make([]byte, 3)
+	memory.Load(saved, 0x1234)
+	if !reflect.DeepEqual(saved, []byte{0x01, 0x02, 0x03}) {
+		t.Errorf("[memory] result: %v, expected: %v", saved, []byte{0x01, 0x02, 0x03})
+	}
+
+	if sound.pitch != 0x01 {
+		t.Errorf("[sound pitch] result: %d, expected: %d", sound.pitch, 0x01)
+	}
+
+	expectedPattern := [16]byte{0x01, 0x02, 0x03}
+	if sound.pattern != expectedPattern {
+		t.Errorf("[sound pattern] result: %v, expected: %v", sound.pattern, expectedPattern)
+	}
+
+	if display.planes != 0x3 {
+		t.Errorf("[display planes] result: %d, expected: %d", display.planes, 0x3)
+	}
+
+	expectedDraws := []MockPlaneDraw{
+		{plane: 0x1, MockDraw: MockDraw{xDisplay: 3, yDisplay: 2, sprite: 0xAA}},
+		{plane: 0x2, MockDraw: MockDraw{xDisplay: 3, yDisplay: 2, sprite: 0x55}},
+	}
+	if !reflect.DeepEqual(display.planeDraws, expectedDraws) {
+		t.Errorf("[display planeDraws] result: %v, expected: %v", display.planeDraws, expectedDraws)
+	}
+}
+
+func TestCpu_ProcessErrors(t *testing.T) {
+	tests := []struct {
+		describe string
+		instr    chip8.Instruction
+		sp       byte
+		i        uint16
+		expected error
+	}{
+		{
+			describe: "when opcode is unknown",
+			instr:    chip8.Instruction{0x50, 0x11},
+			expected: &chip8.ErrUnknownOpcode{PC: 0x200, Opcode: 0x5011},
+		},
+		{
+			describe: "when opcode of SUPER-CHIP is not supported by display",
+			instr:    chip8.Instruction{0x00, 0xFF},
+			expected: &chip8.ErrUnknownOpcode{PC: 0x200, Opcode: 0x00FF},
+		},
+		{
+			describe: "when stack is full",
+			instr:    chip8.Instruction{0x23, 0x00},
+			sp:       0x10,
+			expected: chip8.ErrStackOverflow,
+		},
+		{
+			describe: "when stack is empty",
+			instr:    chip8.Instruction{0x00, 0xEE},
+			expected: chip8.ErrStackUnderflow,
+		},
+		{
+			describe: "when memory is accessed out of bounds",
+			instr:    chip8.Instruction{0xF2, 0x55},
+			i:        0xFFE,
+			expected: &chip8.ErrMemoryOutOfBounds{PC: 0x200, Address: 0xFFE, Size: 3},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.describe, func(t *testing.T) {
+			cpu := chip8.NewCpu(&chip8.ConfigCpu{
+				Display: &MockDisplay{},
+				Memory:  chip8.NewStandardMemory(&chip8.ConfigMemory{Rom: &MockRom{}}),
+				I:       test.i,
+				PC:      0x200,
+				SP:      test.sp,
+			})
+
+			err := cpu.Process(test.instr)
+
+			switch expected := test.expected.(type) {
+			case *chip8.ErrUnknownOpcode:
+				result := &chip8.ErrUnknownOpcode{}
+				if !errors.As(err, &result) || *result != *expected {
+					t.Errorf("result: %v, expected: %v", err, expected)
+				}
+			case *chip8.ErrMemoryOutOfBounds:
+				result := &chip8.ErrMemoryOutOfBounds{}
+				if !errors.As(err, &result) || *result != *expected {
+					t.Errorf("result: %v, expected: %v", err, expected)
+				}
+			default:
+				if !errors.Is(err, expected) {
+					t.Errorf("result: %v, expected: %v", err, expected)
+				}
+			}
+
+			if !cpu.Halted() || cpu.Err() != err {
+				t.Errorf("expected halted with error %v, but halted is %v with error %v", err, cpu.Halted(), cpu.Err())
+			}
+
+			if err := cpu.Process(chip8.Instruction{0x60, 0x01}); !errors.Is(err, chip8.ErrHalted) {
+				t.Errorf("result: %v, expected: %v", err, chip8.ErrHalted)
+			}
+		})
+	}
+}
+
+func TestCpu_StartError(t *testing.T) {
+	memory := chip8.NewStandardMemory(&chip8.ConfigMemory{Rom: bytes.NewReader([]byte{0x60, 0x01, 0x50, 0x01})})
+
+	cpu := chip8.NewCpu(&chip8.ConfigCpu{
+		Memory: memory,
+		Sound:  &MockSound{},
+		PC:     0x200,
+	})
+
+	err := cpu.Start(context.Background())
+
+	result := &chip8.ErrUnknownOpcode{}
+	if !errors.As(err, &result) || result.PC != 0x202 || result.Opcode != 0x5001 {
+		t.Errorf("result: %v, expected: %v", err, &chip8.ErrUnknownOpcode{PC: 0x202, Opcode: 0x5001})
+	}
+}
+
+func TestCpu_StartCancel(t *testing.T) {
+	// Infinite loop: jump to 0x200
+	memory := chip8.NewStandardMemory(&chip8.ConfigMemory{Rom: bytes.NewReader([]byte{0x12, 0x00})})
+
+	cpu := chip8.NewCpu(&chip8.ConfigCpu{
+		Memory: memory,
+		Sound:  &MockSound{},
+		PC:     0x200,
+	})
+
+	goroutines := runtime.NumGoroutine()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := cpu.Start(ctx)
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("result: %v, expected: %v", err, context.DeadlineExceeded)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Start returned after %v", elapsed)
+	}
+
+	if cpu.Halted() {
+		t.Errorf("expected running, but it is halted")
+	}
+
+	if result := runtime.NumGoroutine(); result != goroutines {
+		t.Errorf("[goroutines] result: %d, expected: %d", result, goroutines)
+	}
+}
+
+func TestCpu_Step(t *testing.T) {
+	// V0 := 5, then V0 += 1 forever
+	memory := chip8.NewStandardMemory(&chip8.ConfigMemory{Rom: bytes.NewReader([]byte{0x60, 0x05, 0x70, 0x01, 0x12, 0x02})})
+	log := &bytes.Buffer{}
+
+	cpu := chip8.NewCpu(&chip8.ConfigCpu{
+		Memory: memory,
+		Log:    log,
+		PC:     0x200,
+	})
+
+	for i := 0; i < 2; i++ {
+		if err := cpu.Step(); err != nil {
+			t.Fatalf("error not expected: %s", err.Error())
+		}
+	}
+
+	if err := cpu.RunCycles(4); err != nil {
+		t.Fatalf("error not expected: %s", err.Error())
+	}
+
+	cpu.Log()
+
+	result := log.Bytes()
+	expected := cpuToStr(0x204, 0x0, chip8.Register{0x08}, chip8.Stack{}, 0x0, 0x0, 0x0)
+	if string(result) != string(expected) {
+		t.Errorf("result: %s, expected: %s", result, expected)
+	}
+}
+
+func TestCpu_RunFrame(t *testing.T) {
+	// V0 := 2, DT := V0, ST := V0, then loop forever
+	memory := chip8.NewStandardMemory(&chip8.ConfigMemory{Rom: bytes.NewReader([]byte{0x60, 0x02, 0xF0, 0x15, 0xF0, 0x18, 0x12, 0x06})})
+	sound := MockSound{}
+	log := &bytes.Buffer{}
+
+	cpu := chip8.NewCpu(&chip8.ConfigCpu{
+		Memory:               memory,
+		Sound:                &sound,
+		Log:                  log,
+		InstructionsPerFrame: 4,
+		PC:                   0x200,
+	})
+
+	if err := cpu.RunFrame(); err != nil {
+		t.Fatalf("error not expected: %s", err.Error())
+	}
+
+	cpu.Log()
+
+	result := log.Bytes()
+	expected := cpuToStr(0x206, 0x0, chip8.Register{0x02}, chip8.Stack{}, 0x0, 0x1, 0x1)
+	if string(result) != string(expected) {
+		t.Errorf("result: %s, expected: %s", result, expected)
+	}
+
+	if sound.beepCount != 0 {
+		t.Errorf("[sound beepCount] result: %d, expected: %d", sound.beepCount, 0)
+	}
+
+	if err := cpu.RunFrame(); err != nil {
+		t.Fatalf("error not expected: %s", err.Error())
+	}
+
+	if sound.beepCount != 1 {
+		t.Errorf("[sound beepCount] result: %d, expected: %d", sound.beepCount, 1)
+	}
+
+	t.Run("when sound plays the pattern", func(t *testing.T) {
+		memory := chip8.NewStandardMemory(&chip8.ConfigMemory{Rom: bytes.NewReader([]byte{0x60, 0x02, 0xF0, 0x15, 0xF0, 0x18, 0x12, 0x06})})
+		sound := MockPatternSound{}
+
+		cpu := chip8.NewCpu(&chip8.ConfigCpu{
+			Memory:               memory,
+			Sound:                &sound,
+			InstructionsPerFrame: 4,
+			PC:                   0x200,
+		})
+
+		for i := 0; i < 3; i++ {
+			if err := cpu.RunFrame(); err != nil {
+				t.Fatalf("error not expected: %s", err.Error())
+			}
+		}
+
+		// It plays since ST is set until ST reaches zero
+		if expected := []bool{true, false}; !reflect.DeepEqual(sound.playing, expected) {
+			t.Errorf("result: %v, expected: %v", sound.playing, expected)
+		}
+
+		if sound.beepCount != 1 {
+			t.Errorf("[sound beepCount] result: %d, expected: %d", sound.beepCount, 1)
+		}
+	})
+}
+
+func TestCpu_RunCyclesHalted(t *testing.T) {
+	memory := chip8.NewStandardMemory(&chip8.ConfigMemory{Rom: bytes.NewReader([]byte{0x60, 0x01, 0x00, 0xFD, 0x60, 0x02})})
+
+	cpu := chip8.NewCpu(&chip8.ConfigCpu{
+		Memory: memory,
+		PC:     0x200,
+	})
+
+	if err := cpu.RunCycles(10); err != nil {
+		t.Fatalf("error not expected: %s", err.Error())
+	}
+
+	if cpu.NextInstruction() != 0x202 {
+		t.Errorf("result: 0x%X, expected: 0x%X", cpu.NextInstruction(), 0x202)
+	}
+
+	if err := cpu.Step(); !errors.Is(err, chip8.ErrHalted) {
+		t.Errorf("result: %v, expected: %v", err, chip8.ErrHalted)
+	}
+}
+
+// timerRom sets DT to V0 and waits it reaches zero, then halts
+func timerRom(dt byte) []byte {
+	return []byte{
+		// V0 := dt, DT := V0
+		0x60, dt, 0xF0, 0x15,
+		// V1 := DT, skip if V1 == 0
+		0xF1, 0x07, 0x31, 0x00,
+		// Jump to V1 := DT
+		0x12, 0x04,
+		// Exit
+		0x00, 0xFD,
+	}
+}
+
+func TestCpu_StartVirtualClock(t *testing.T) {
+	run := func() (time.Duration, time.Duration) {
+		memory := chip8.NewStandardMemory(&chip8.ConfigMemory{Rom: bytes.NewReader(timerRom(60))})
+		clock := chip8.NewVirtualClock()
+
+		cpu := chip8.NewCpu(&chip8.ConfigCpu{
+			Memory: memory,
+			Sound:  &MockSound{},
+			Clock:  clock,
+			PC:     0x200,
+		})
+
+		if err := cpu.Start(context.Background()); err != nil {
+			t.Fatalf("error not expected: %s", err.Error())
+		}
+
+		return clock.Now(), cpu.Elapsed()
+	}
+
+	start := time.Now()
+	clockNow, elapsed := run()
+
+	if wall := time.Since(start); wall > 500*time.Millisecond {
+		t.Errorf("virtual clock is not expected to sleep, but took %v", wall)
+	}
+
+	// DT reaches zero after 60 frames
+	if elapsed < time.Second || elapsed > time.Second+time.Second/60 {
+		t.Errorf("[elapsed] result: %v, expected: about %v", elapsed, time.Second)
+	}
+
+	if clockNow < elapsed-time.Second/60 || clockNow > elapsed+time.Second/60 {
+		t.Errorf("[clock] result: %v, expected: about %v", clockNow, elapsed)
+	}
+
+	clockNowAgain, elapsedAgain := run()
+	if clockNowAgain != clockNow || elapsedAgain != elapsed {
+		t.Errorf("result: (%v, %v), expected: (%v, %v)", clockNowAgain, elapsedAgain, clockNow, elapsed)
+	}
+}
+
+func TestCpu_StartRealClock(t *testing.T) {
+	memory := chip8.NewStandardMemory(&chip8.ConfigMemory{Rom: bytes.NewReader(timerRom(6))})
+
+	cpu := chip8.NewCpu(&chip8.ConfigCpu{
+		Memory: memory,
+		Sound:  &MockSound{},
+		PC:     0x200,
+	})
+
+	start := time.Now()
+	if err := cpu.Start(context.Background()); err != nil {
+		t.Fatalf("error not expected: %s", err.Error())
+	}
+
+	// DT reaches zero after 6 frames (100ms)
+	if wall := time.Since(start); wall < 80*time.Millisecond {
+		t.Errorf("result: %v, expected about: %v", wall, 100*time.Millisecond)
+	}
+}
+
+func TestCpu_StartConcurrent(t *testing.T) {
+	output := &syncWriter{}
+	log := &bytes.Buffer{}
+
+	cpu := chip8.NewCpu(&chip8.ConfigCpu{
+		Display:  chip8.NewStandardDisplay(&chip8.ConfigDisplay{Output: output}),
+		Keyboard: MockKeyBoard{Key: 0xFF},
+		Memory:   loadRom(t, "counter.ch8"),
+		Sound:    &MockSound{},
+		Log:      log,
+		PC:       0x200,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- cpu.Start(ctx)
+	}()
+
+	// Reads the state of Cpu while it is running
+	deadline := time.Now().Add(100 * time.Millisecond)
+	for time.Now().Before(deadline) {
+		cpu.Log()
+		cpu.NextInstruction()
+		cpu.Halted()
+		cpu.Err()
+		cpu.Elapsed()
+		time.Sleep(time.Millisecond)
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("result: %v, expected: %v", err, context.Canceled)
+	}
+
+	if cpu.Elapsed() == 0 {
+		t.Errorf("expected emulated time elapsed, but it is zero")
+	}
+
+	if output.Len() == 0 {
+		t.Errorf("expected output on display, but it is empty")
+	}
+}
+
+func TestCpu_ProcessRandom(t *testing.T) {
+	run := func(seed int64) []byte {
+		memory := chip8.NewStandardMemory(&chip8.ConfigMemory{Rom: &MockRom{}})
+		cpu := chip8.NewCpu(&chip8.ConfigCpu{
+			Memory: memory,
+			Random: rand.New(rand.NewSource(seed)),
+			I:      0x300,
+		})
+
+		values := []byte{}
+		for i := 0; i < 2048; i++ {
+			// V0 := random, save V0 on I
+			for _, instr := range []chip8.Instruction{{0xC0, 0xFF}, {0xF0, 0x55}} {
+				if err := cpu.Process(instr); err != nil {
+					t.Fatalf("error not expected: %s", err.Error())
+				}
+			}
+			values = append(values, memory.LoadSprite(0x300))
+		}
+
+		return values
+	}
+
+	values := run(51153153)
+
+	if !reflect.DeepEqual(values, run(51153153)) {
+		t.Errorf("expected the same numbers for the same seed")
+	}
+
+	seen := map[byte]bool{}
+	for _, value := range values {
+		seen[value] = true
+	}
+
+	if !seen[0x00] || !seen[0xFF] {
+		t.Errorf("expected numbers on range 0x00 - 0xFF, but 0x00 is %v and 0xFF is %v", seen[0x00], seen[0xFF])
+	}
+}
+
 func checkDisplay(t *testing.T, display MockDisplay, context cpuTestCaseContext) {
 	t.Helper()
 
@@ -713,3 +1552,307 @@ func cpuToStr(pc, i uint16, register chip8.Register, stack chip8.Stack, sp, dt,
 
 	return []byte(str)
 }
+
+func TestCpu_SetState(t *testing.T) {
+	memory := chip8.NewStandardMemory(&chip8.ConfigMemory{Rom: &MockRom{}})
+	display := chip8.NewStandardDisplay(&chip8.ConfigDisplay{Output: &bytes.Buffer{}})
+	cpu := chip8.NewCpu(&chip8.ConfigCpu{Memory: memory, Display: display, PC: 0x200})
+
+	expected := chip8.State{
+		Register: chip8.Register{0x1, 0x2, 0x3},
+		Stack:    chip8.Stack{0x208, 0x30A},
+		Flags:    chip8.Register{0xF},
+		PC:       0x400,
+		I:        0x300,
+		SP:       2,
+		DT:       0x10,
+		ST:       0x20,
+		Planes:   0x3,
+	}
+
+	if err := cpu.SetState(expected); err != nil {
+		t.Fatalf("error not expected: %s", err.Error())
+	}
+
+	if result := cpu.State(); result != expected {
+		t.Errorf("result: %+v, expected: %+v", result, expected)
+	}
+
+	t.Run("when Cpu is halted", func(t *testing.T) {
+		if err := cpu.Process(chip8.Instruction{0x00, 0x00}); err == nil {
+			t.Fatalf("result: nil, expected: error")
+		}
+
+		if err := cpu.SetState(expected); err != nil {
+			t.Fatalf("error not expected: %s", err.Error())
+		}
+
+		if cpu.Halted() || cpu.Err() != nil {
+			t.Errorf("result: %v and %v, expected: false and nil", cpu.Halted(), cpu.Err())
+		}
+	})
+
+	t.Run("when a value is out of range", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			state chip8.State
+		}{
+			{"pc", chip8.State{PC: 0xFFF}},
+			{"i", chip8.State{I: 0x1000}},
+			{"sp", chip8.State{SP: 17}},
+			{"stack", chip8.State{SP: 1, Stack: chip8.Stack{0x1000}}},
+			{"planes", chip8.State{Planes: 0x4}},
+		}
+
+		for _, test := range tests {
+			if err := cpu.SetState(test.state); !errors.Is(err, chip8.ErrOutOfRange) {
+				t.Errorf("[%s] result: %v, expected: %v", test.name, err, chip8.ErrOutOfRange)
+			}
+		}
+
+		if result := cpu.State(); result != expected {
+			t.Errorf("result: %+v, expected: %+v", result, expected)
+		}
+	})
+}
+
+func TestCpu_Setters(t *testing.T) {
+	memory := chip8.NewStandardMemory(&chip8.ConfigMemory{Rom: &MockRom{}})
+	cpu := chip8.NewCpu(&chip8.ConfigCpu{Memory: memory, PC: 0x200})
+
+	if err := cpu.SetPC(0x300); err != nil {
+		t.Fatalf("error not expected: %s", err.Error())
+	}
+	if err := cpu.SetI(0x400); err != nil {
+		t.Fatalf("error not expected: %s", err.Error())
+	}
+	if err := cpu.SetRegister(0xA, 0x42); err != nil {
+		t.Fatalf("error not expected: %s", err.Error())
+	}
+
+	state := cpu.State()
+	if state.PC != 0x300 || state.I != 0x400 || state.Register[0xA] != 0x42 {
+		t.Errorf("result: 0x%03X, 0x%03X and 0x%02X, expected: 0x300, 0x400 and 0x42", state.PC, state.I, state.Register[0xA])
+	}
+
+	errs := []error{cpu.SetPC(0xFFF), cpu.SetI(0x1000), cpu.SetRegister(0x10, 0x1)}
+	for i, err := range errs {
+		if !errors.Is(err, chip8.ErrOutOfRange) {
+			t.Errorf("[%d] result: %v, expected: %v", i, err, chip8.ErrOutOfRange)
+		}
+	}
+
+	if result := cpu.State(); result != state {
+		t.Errorf("result: %+v, expected: %+v", result, state)
+	}
+
+	t.Run("when values are on the end of memory", func(t *testing.T) {
+		for i, err := range []error{cpu.SetPC(0xFFE), cpu.SetI(0xFFF)} {
+			if err != nil {
+				t.Errorf("[%d] error not expected: %s", i, err.Error())
+			}
+		}
+	})
+}
+
+func TestCpu_Reset(t *testing.T) {
+	expected := newStateMachine(t).snapshot(t, 60)
+
+	machine := newStateMachine(t)
+	machine.snapshot(t, 100)
+	if err := machine.cpu.Process(chip8.Instruction{0x00, 0x00}); err == nil {
+		t.Fatalf("result: nil, expected: error")
+	}
+
+	machine.cpu.Reset()
+
+	if machine.cpu.Halted() {
+		t.Errorf("expected Cpu running, but it is halted")
+	}
+
+	if result := machine.snapshot(t, 60); result != expected {
+		t.Errorf("result:\n%s\nexpected:\n%s\n", result, expected)
+	}
+
+	t.Run("when PC is not configured", func(t *testing.T) {
+		cpu := chip8.NewCpu(&chip8.ConfigCpu{})
+		cpu.Reset()
+
+		if result := cpu.State().PC; result != 0x200 {
+			t.Errorf("result: 0x%03X, expected: 0x%03X", result, 0x200)
+		}
+	})
+}
+
+func TestCpu_LoadROM(t *testing.T) {
+	machine := newStateMachine(t)
+	machine.snapshot(t, 10)
+
+	if err := machine.cpu.LoadROM(bytes.NewReader([]byte{0x60, 0x42, 0x12, 0x02})); err != nil {
+		t.Fatalf("error not expected: %s", err.Error())
+	}
+	machine.snapshot(t, 1)
+
+	state := machine.cpu.State()
+	if state.Register[0x0] != 0x42 || state.PC != 0x202 || state.SP != 0 {
+		t.Errorf("result: V0 = 0x%02X, PC = 0x%03X, SP = %d, expected: V0 = 0x42, PC = 0x202, SP = 0", state.Register[0x0], state.PC, state.SP)
+	}
+
+	t.Run("when memory is not resettable", func(t *testing.T) {
+		cpu := chip8.NewCpu(&chip8.ConfigCpu{Memory: &MockMemory{}})
+
+		if err := cpu.LoadROM(bytes.NewReader(nil)); !errors.Is(err, chip8.ErrNotResettable) {
+			t.Errorf("result: %v, expected: %v", err, chip8.ErrNotResettable)
+		}
+	})
+
+	t.Run("when ROM is read slowly", func(t *testing.T) {
+		reader, writer := io.Pipe()
+		done := make(chan error)
+		go func() {
+			done <- machine.cpu.LoadROM(reader)
+		}()
+
+		// Cpu is not locked while the ROM is read
+		state := make(chan chip8.State)
+		go func() {
+			state <- machine.cpu.State()
+		}()
+
+		select {
+		case <-state:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("State blocked while ROM is read")
+		}
+
+		writer.Write([]byte{0x60, 0x24})
+		writer.Close()
+		if err := <-done; err != nil {
+			t.Fatalf("error not expected: %s", err.Error())
+		}
+
+		if err := machine.cpu.Step(); err != nil {
+			t.Fatalf("error not expected: %s", err.Error())
+		}
+
+		if result := machine.cpu.State().Register[0x0]; result != 0x24 {
+			t.Errorf("result: 0x%02X, expected: 0x%02X", result, 0x24)
+		}
+	})
+}
+
+func TestCpu_SetSpeed(t *testing.T) {
+	memory := chip8.NewStandardMemory(&chip8.ConfigMemory{Rom: bytes.NewReader(timerRom(60))})
+	clock := chip8.NewVirtualClock()
+	cpu := chip8.NewCpu(&chip8.ConfigCpu{Memory: memory, Sound: &MockSound{}, Clock: clock, PC: 0x200})
+
+	tests := []struct{ speed, expected int }{{1200, 1200}, {500, 480}, {30, 60}, {0, 0}}
+	for _, test := range tests {
+		cpu.SetSpeed(test.speed)
+		if result := cpu.Speed(); result != test.expected {
+			t.Errorf("[%d] result: %d, expected: %d", test.speed, result, test.expected)
+		}
+	}
+
+	t.Run("when speed is unlimited", func(t *testing.T) {
+		if err := cpu.Start(context.Background()); err != nil {
+			t.Fatalf("error not expected: %s", err.Error())
+		}
+
+		if elapsed := cpu.Elapsed(); elapsed < time.Second {
+			t.Errorf("result: %v, expected: at least %v", elapsed, time.Second)
+		}
+
+		if result := clock.Now(); result != 0 {
+			t.Errorf("result: %v, expected: %v", result, time.Duration(0))
+		}
+	})
+
+	t.Run("when speed changes", func(t *testing.T) {
+		cpu.Reset()
+		cpu.SetSpeed(1200)
+
+		if err := cpu.RunCycles(20); err != nil {
+			t.Fatalf("error not expected: %s", err.Error())
+		}
+
+		if result := cpu.Elapsed(); result != time.Second/60 {
+			t.Errorf("result: %v, expected: %v", result, time.Second/60)
+		}
+	})
+}
+
+func TestCpu_Pause(t *testing.T) {
+	rom := []byte{
+		// V0 := FF, DT := V0, jump to itself
+		0x60, 0xFF, 0xF0, 0x15, 0x12, 0x04,
+	}
+	memory := chip8.NewStandardMemory(&chip8.ConfigMemory{Rom: bytes.NewReader(rom)})
+	cpu := chip8.NewCpu(&chip8.ConfigCpu{Memory: memory, Sound: &MockSound{}, PC: 0x200})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- cpu.Start(ctx)
+	}()
+
+	// waitTimer waits until DT is set and below dt
+	waitTimer := func(dt byte) {
+		t.Helper()
+
+		deadline := time.Now().Add(5 * time.Second)
+		for current := cpu.State().DT; current == 0 || current >= dt; current = cpu.State().DT {
+			if time.Now().After(deadline) {
+				t.Fatalf("DT did not go below 0x%02X", dt)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	waitTimer(0xFF)
+	cpu.Pause()
+	if !cpu.Paused() {
+		t.Fatalf("expected Cpu paused, but it is running")
+	}
+
+	// The frame in progress when paused may finish
+	time.Sleep(20 * time.Millisecond)
+	paused, elapsed := cpu.State(), cpu.Elapsed()
+	time.Sleep(50 * time.Millisecond)
+	if result := cpu.State(); result != paused || cpu.Elapsed() != elapsed {
+		t.Errorf("result: %+v, expected: %+v", result, paused)
+	}
+
+	t.Run("when a frame is advanced", func(t *testing.T) {
+		if err := cpu.FrameAdvance(); err != nil {
+			t.Fatalf("error not expected: %s", err.Error())
+		}
+
+		if result := cpu.State().DT; result != paused.DT-1 {
+			t.Errorf("result: 0x%02X, expected: 0x%02X", result, paused.DT-1)
+		}
+
+		// Elapsed is rounded down to nanoseconds
+		frames := (elapsed + time.Millisecond) * 60 / time.Second
+		if result, expected := cpu.Elapsed(), (frames+1)*time.Second/60; result != expected {
+			t.Errorf("result: %v, expected: %v", result, expected)
+		}
+	})
+
+	cpu.Resume()
+	waitTimer(paused.DT - 2)
+
+	t.Run("when context is cancelled while paused", func(t *testing.T) {
+		cpu.Pause()
+		cancel()
+
+		select {
+		case err := <-done:
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("result: %v, expected: %v", err, context.Canceled)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Start did not return")
+		}
+	})
+}
@@ -0,0 +1,276 @@
+package chip8_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	chip8 "github.com/MarceloMPJR/go-chip-8"
+	"github.com/MarceloMPJR/go-chip-8/debugger"
+)
+
+func newDebugger(t *testing.T) (*debugger.Debugger, *chip8.Cpu) {
+	t.Helper()
+
+	memory := loadRom(t, "counter.ch8")
+	cpu := chip8.NewCpu(&chip8.ConfigCpu{
+		Display:  &MockDisplay{},
+		Keyboard: MockKeyBoard{Key: 0xFF},
+		Memory:   memory,
+		Sound:    &MockSound{},
+		Random:   chip8.NewSequenceRandom(0x07),
+		PC:       0x200,
+	})
+
+	return debugger.NewDebugger(&debugger.ConfigDebugger{Cpu: cpu, Memory: memory}), cpu
+}
+
+func checkStop(t *testing.T, stop debugger.Stop, err error, reason debugger.Reason, pc uint16) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("error not expected: %s", err.Error())
+	}
+
+	if stop.Reason != reason || stop.PC != pc {
+		t.Errorf("result: %s at 0x%03X, expected: %s at 0x%03X", stop.Reason, stop.PC, reason, pc)
+	}
+}
+
+func TestDebugger_Breakpoint(t *testing.T) {
+	dbg, cpu := newDebugger(t)
+	dbg.AddBreakpoint(0x220, nil)
+
+	stop, err := dbg.Continue(context.Background())
+	checkStop(t, stop, err, debugger.ReasonBreakpoint, 0x220)
+
+	expected := []debugger.Frame{{Caller: 0x206, Entry: 0x220, Return: 0x208}}
+	if result := dbg.CallStack(); len(result) != 1 || result[0] != expected[0] {
+		t.Errorf("result: %v, expected: %v", result, expected)
+	}
+
+	t.Run("when it continues from the breakpoint", func(t *testing.T) {
+		stop, err := dbg.Continue(context.Background())
+		checkStop(t, stop, err, debugger.ReasonBreakpoint, 0x220)
+
+		if result := cpu.State().Register[5]; result != 1 {
+			t.Errorf("result: %d, expected: %d", result, 1)
+		}
+	})
+
+	t.Run("when the breakpoint is removed", func(t *testing.T) {
+		dbg.RemoveBreakpoint(0x220)
+		dbg.AddBreakpoint(0x216, nil)
+
+		stop, err := dbg.Continue(context.Background())
+		checkStop(t, stop, err, debugger.ReasonBreakpoint, 0x216)
+	})
+}
+
+func TestDebugger_ConditionalBreakpoint(t *testing.T) {
+	dbg, cpu := newDebugger(t)
+
+	condition, err := debugger.Compare(0x5, ">=", 3)
+	if err != nil {
+		t.Fatalf("error not expected: %s", err.Error())
+	}
+	dbg.AddBreakpoint(0x216, condition)
+
+	stop, err := dbg.Continue(context.Background())
+	checkStop(t, stop, err, debugger.ReasonBreakpoint, 0x216)
+
+	if result := cpu.State().Register[5]; result != 3 {
+		t.Errorf("result: %d, expected: %d", result, 3)
+	}
+
+	t.Run("when the condition is invalid", func(t *testing.T) {
+		if _, err := debugger.Compare(0x10, "==", 0); err == nil {
+			t.Errorf("error expected for register")
+		}
+
+		if _, err := debugger.Compare(0x0, "=<", 0); err == nil {
+			t.Errorf("error expected for operator")
+		}
+	})
+}
+
+func TestDebugger_Watchpoint(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		watchpoint debugger.Watchpoint
+		access     debugger.Access
+		address    uint16
+		pc         uint16
+	}{
+		{
+			desc:       "when FX33 writes on watched address",
+			watchpoint: debugger.Watchpoint{Address: 0x301, Size: 1, Access: debugger.Write},
+			access:     debugger.Write,
+			address:    0x301,
+			pc:         0x224,
+		},
+		{
+			desc:       "when FX65 reads a watched range",
+			watchpoint: debugger.Watchpoint{Address: 0x2FE, Size: 4, Access: debugger.Read},
+			access:     debugger.Read,
+			address:    0x300,
+			pc:         0x226,
+		},
+		{
+			desc:       "when DXYN reads the sprite of font",
+			watchpoint: debugger.Watchpoint{Address: 0x000, Size: 5, Access: debugger.ReadWrite},
+			access:     debugger.Read,
+			address:    0x000,
+			pc:         0x22E,
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			dbg, _ := newDebugger(t)
+			dbg.AddWatchpoint(tC.watchpoint)
+
+			stop, err := dbg.Continue(context.Background())
+			checkStop(t, stop, err, debugger.ReasonWatchpoint, tC.pc)
+
+			if stop.Access != tC.access || stop.Address != tC.address || stop.Watchpoint != tC.watchpoint {
+				t.Errorf("result: %s 0x%03X, expected: %s 0x%03X", stop.Access, stop.Address, tC.access, tC.address)
+			}
+		})
+	}
+
+	t.Run("when the watchpoint is removed", func(t *testing.T) {
+		dbg, _ := newDebugger(t)
+		dbg.AddWatchpoint(debugger.Watchpoint{Address: 0x300, Size: 3, Access: debugger.ReadWrite})
+		dbg.RemoveWatchpoint(0x300)
+		dbg.AddBreakpoint(0x22A, nil)
+
+		stop, err := dbg.Continue(context.Background())
+		checkStop(t, stop, err, debugger.ReasonBreakpoint, 0x22A)
+	})
+}
+
+func TestDebugger_Steps(t *testing.T) {
+	t.Run("when it steps over a call", func(t *testing.T) {
+		dbg, cpu := newDebugger(t)
+		dbg.AddBreakpoint(0x206, nil)
+		dbg.Continue(context.Background())
+
+		stop, err := dbg.StepOver()
+		checkStop(t, stop, err, debugger.ReasonStep, 0x208)
+
+		if result := cpu.State().SP; result != 0 {
+			t.Errorf("result: %d, expected: %d", result, 0)
+		}
+	})
+
+	t.Run("when it steps over other instruction", func(t *testing.T) {
+		dbg, _ := newDebugger(t)
+
+		stop, err := dbg.StepOver()
+		checkStop(t, stop, err, debugger.ReasonStep, 0x202)
+	})
+
+	t.Run("when it steps over a call with breakpoint", func(t *testing.T) {
+		dbg, _ := newDebugger(t)
+		dbg.AddBreakpoint(0x206, nil)
+		dbg.Continue(context.Background())
+		dbg.AddBreakpoint(0x22C, nil)
+
+		stop, err := dbg.StepOver()
+		checkStop(t, stop, err, debugger.ReasonBreakpoint, 0x22C)
+	})
+
+	t.Run("when it steps out", func(t *testing.T) {
+		dbg, _ := newDebugger(t)
+		dbg.AddBreakpoint(0x22C, nil)
+		dbg.Continue(context.Background())
+
+		stop, err := dbg.StepOut()
+		checkStop(t, stop, err, debugger.ReasonStep, 0x208)
+
+		if _, err := dbg.StepOut(); !errors.Is(err, debugger.ErrNoCaller) {
+			t.Errorf("result: %v, expected: %v", err, debugger.ErrNoCaller)
+		}
+	})
+
+	t.Run("when it steps into a call", func(t *testing.T) {
+		dbg, _ := newDebugger(t)
+		for i := 0; i < 3; i++ {
+			dbg.Step()
+		}
+
+		stop, err := dbg.Step()
+		checkStop(t, stop, err, debugger.ReasonStep, 0x220)
+	})
+}
+
+func TestDebugger_Halted(t *testing.T) {
+	memory := chip8.NewStandardMemory(&chip8.ConfigMemory{Rom: bytes.NewReader([]byte{0x60, 0x01, 0x00, 0xFD})})
+	cpu := chip8.NewCpu(&chip8.ConfigCpu{Display: &MockDisplay{}, Memory: memory, Sound: &MockSound{}, PC: 0x200})
+	dbg := debugger.NewDebugger(&debugger.ConfigDebugger{Cpu: cpu, Memory: memory})
+
+	stop, err := dbg.Continue(context.Background())
+	checkStop(t, stop, err, debugger.ReasonHalted, 0x202)
+
+	if _, err := dbg.Step(); !errors.Is(err, chip8.ErrHalted) {
+		t.Errorf("result: %v, expected: %v", err, chip8.ErrHalted)
+	}
+}
+
+func TestDebugger_ContinueCancel(t *testing.T) {
+	dbg, _ := newDebugger(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := dbg.Continue(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("result: %v, expected: %v", err, context.Canceled)
+	}
+}
+
+func TestDebugger_Console(t *testing.T) {
+	dbg, _ := newDebugger(t)
+
+	commands := []string{
+		"break 0x220",
+		"continue",
+		"stack",
+		"delete 0x220",
+		"watch 0x300 3 w",
+		"c",
+		"mem 0x300 3",
+		"unwatch 0x300",
+		"break 0x216 if V5 == 2",
+		"c",
+		"regs",
+		"finish",
+		"jump",
+		"quit",
+	}
+	in := strings.NewReader(strings.Join(commands, "\n"))
+	out := &bytes.Buffer{}
+
+	if err := dbg.Console(context.Background(), in, out); err != nil {
+		t.Fatalf("error not expected: %s", err.Error())
+	}
+
+	expected := []string{
+		"breakpoint, pc=0x220",
+		"#0 0x220 called from 0x206, returns to 0x208",
+		"watchpoint write 0x300, pc=0x224",
+		"0x300: 00 00 00",
+		"breakpoint, pc=0x216",
+		"pc=0x216 i=0x005 sp=0 dt=0 st=0",
+		"V0=0A V1=00 V2=01 V3=0A V4=00 V5=02 V6=00 V7=00",
+		"error: no subroutine to step out",
+		"error: unknown command \"jump\", try help",
+	}
+	for _, line := range expected {
+		if !strings.Contains(out.String(), line+"\n") {
+			t.Errorf("result:\n%s\nexpected to contain: %s", out.String(), line)
+		}
+	}
+}
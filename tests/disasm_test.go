@@ -0,0 +1,130 @@
+package chip8_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/MarceloMPJR/go-chip-8/disasm"
+)
+
+func TestDisassembler_Write(t *testing.T) {
+	rom, err := os.ReadFile(filepath.Join("testdata", "counter.ch8"))
+	if err != nil {
+		t.Fatalf("error not expected: %s", err.Error())
+	}
+
+	testCases := []struct {
+		desc   string
+		syntax disasm.Syntax
+		golden string
+	}{
+		{desc: "when syntax is Cowgod", syntax: disasm.Cowgod, golden: "counter.asm"},
+		{desc: "when syntax is Octo", syntax: disasm.Octo, golden: "counter.8o"},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			expected, err := os.ReadFile(filepath.Join("testdata", tC.golden))
+			if err != nil {
+				t.Fatalf("error not expected: %s", err.Error())
+			}
+
+			result := &bytes.Buffer{}
+			dis := disasm.NewDisassembler(&disasm.ConfigDisassembler{Syntax: tC.syntax})
+			if err := dis.Write(result, rom); err != nil {
+				t.Fatalf("error not expected: %s", err.Error())
+			}
+
+			if result.String() != string(expected) {
+				t.Errorf("result:\n%s\nexpected:\n%s\n", result.String(), expected)
+			}
+		})
+	}
+}
+
+func TestDisassembler_Disassemble(t *testing.T) {
+	rom := []byte{
+		0xA2, 0x0C, // 0x200 LD I, L20C
+		0x32, 0x01, // 0x202 SE V2, 0x01
+		0xF0, 0x00, // 0x204 LD I, LONG L20D
+		0x02, 0x0D, //
+		0x22, 0x10, // 0x208 CALL L210
+		0x12, 0x08, // 0x20A JP L208
+		0xFF, 0x80, // 0x20C data
+		0x81,       // 0x20E data
+		0x00,       // 0x20F data
+		0xB2, 0x14, // 0x210 JP V0, L214
+		0x00, 0x00, // 0x212 data
+		0x00, 0xEE, // 0x214 RET
+		0x00, 0xEE, // 0x216 RET
+	}
+
+	expected := []struct {
+		address uint16
+		code    bool
+		label   string
+		cowgod  string
+		octo    string
+	}{
+		{0x200, true, "", "LD I, L20C", "i := L20C"},
+		{0x202, true, "", "SE V2, 0x01", "if v2 != 0x01 then"},
+		{0x204, true, "", "LD I, LONG L20D", "i := long L20D"},
+		{0x208, true, "L208", "CALL L210", "L210"},
+		{0x20A, true, "", "JP L208", "jump L208"},
+		{0x20C, false, "L20C", "DB 0xFF", "0xFF"},
+		{0x20D, false, "L20D", "DB 0x80, 0x81, 0x00", "0x80 0x81 0x00"},
+		{0x210, true, "L210", "JP V0, L214", "jump0 L214"},
+		{0x212, false, "", "DB 0x00, 0x00", "0x00 0x00"},
+		{0x214, true, "L214", "RET", "return"},
+		{0x216, false, "", "DB 0x00, 0xEE", "0x00 0xEE"},
+	}
+
+	cowgod := disasm.NewDisassembler(&disasm.ConfigDisassembler{}).Disassemble(rom)
+	octo := disasm.NewDisassembler(&disasm.ConfigDisassembler{Syntax: disasm.Octo}).Disassemble(rom)
+
+	if len(cowgod) != len(expected) || len(octo) != len(expected) {
+		t.Fatalf("result: %d lines, expected: %d lines", len(cowgod), len(expected))
+	}
+
+	for idx, line := range expected {
+		if cowgod[idx].Address != line.address || cowgod[idx].Code != line.code || cowgod[idx].Label != line.label {
+			t.Errorf("result: %+v, expected: %+v", cowgod[idx], line)
+		}
+
+		if cowgod[idx].Text != line.cowgod {
+			t.Errorf("result: %s, expected: %s", cowgod[idx].Text, line.cowgod)
+		}
+
+		if octo[idx].Text != line.octo {
+			t.Errorf("result: %s, expected: %s", octo[idx].Text, line.octo)
+		}
+	}
+
+	t.Run("when the ROM is loaded on other address", func(t *testing.T) {
+		dis := disasm.NewDisassembler(&disasm.ConfigDisassembler{Origin: 0x600, Comments: true})
+
+		result := &bytes.Buffer{}
+		if err := dis.Write(result, []byte{0x16, 0x00}); err != nil {
+			t.Fatalf("error not expected: %s", err.Error())
+		}
+
+		if expected := "L600:\n\tJP L600                  ; 0x600  1600\n"; result.String() != expected {
+			t.Errorf("result: %q, expected: %q", result.String(), expected)
+		}
+	})
+
+	t.Run("when the ROM is empty", func(t *testing.T) {
+		if result := disasm.NewDisassembler(&disasm.ConfigDisassembler{}).Disassemble(nil); len(result) != 0 {
+			t.Errorf("result: %v, expected empty", result)
+		}
+	})
+
+	t.Run("when the entry is labeled on Octo", func(t *testing.T) {
+		if octo[0].Label != "main" || !strings.HasPrefix(octo[0].Text, "i :=") {
+			t.Errorf("result: %+v, expected label main", octo[0])
+		}
+	})
+}
@@ -0,0 +1,160 @@
+package chip8_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	chip8 "github.com/MarceloMPJR/go-chip-8"
+)
+
+// recordHooks returns hooks that append a description of each event to events
+func recordHooks(events *[]string) chip8.Hooks {
+	record := func(format string, args ...interface{}) {
+		*events = append(*events, fmt.Sprintf(format, args...))
+	}
+
+	return chip8.Hooks{
+		MemoryRead: func(addr uint16, data []byte) {
+			record("read 0x%03X %v", addr, data)
+		},
+		MemoryWrite: func(addr uint16, data []byte) {
+			record("write 0x%03X %v", addr, data)
+		},
+		DisplayDraw: func(plane, xDisplay, yDisplay, sprite byte, collision bool) {
+			record("draw %d %d,%d %02X %v", plane, xDisplay, yDisplay, sprite, collision)
+		},
+		DisplayClear: func() {
+			record("clear")
+		},
+		KeyPoll: func(key chip8.Key) {
+			record("key %02X", key)
+		},
+		TimerChange: func(timer chip8.Timer, value byte) {
+			record("%s %02X", timer, value)
+		},
+	}
+}
+
+func TestCpu_Hooks(t *testing.T) {
+	var events []string
+	var before, after []uint16
+
+	hooks := recordHooks(&events)
+	hooks.BeforeInstruction = func(instr chip8.Instruction, state chip8.State) {
+		before = append(before, state.PC)
+	}
+	hooks.AfterInstruction = func(instr chip8.Instruction, state chip8.State, err error) {
+		after = append(after, state.PC)
+	}
+
+	cpu := chip8.NewCpu(&chip8.ConfigCpu{
+		Display:  &MockDisplay{},
+		Keyboard: MockKeyBoard{Key: 0xFF},
+		Memory:   loadRom(t, "counter.ch8"),
+		Sound:    &MockSound{},
+		Hooks:    hooks,
+		PC:       0x200,
+	})
+
+	if err := cpu.RunCycles(24); err != nil {
+		t.Fatalf("error not expected: %s", err.Error())
+	}
+
+	expected := []string{
+		"clear", "clear",
+		"write 0x300 [0 0 0]", "read 0x300 [0 0 0]",
+		"read 0x000 [240]", "draw 0 0,0 F0 false",
+		"read 0x001 [144]", "draw 0 0,1 90 false",
+		"read 0x002 [144]", "draw 0 0,2 90 false",
+		"read 0x003 [144]", "draw 0 0,3 90 false",
+		"read 0x004 [240]", "draw 0 0,4 F0 false",
+	}
+	if !reflect.DeepEqual(events[:len(expected)], expected) {
+		t.Errorf("result: %v, expected: %v", events[:len(expected)], expected)
+	}
+
+	timers := []string{}
+	for _, event := range events {
+		if event[0] == 'D' || event[0] == 'S' {
+			timers = append(timers, event)
+		}
+	}
+	if expected := []string{"DT 0A", "ST 0A", "DT 09", "ST 09"}; !reflect.DeepEqual(timers, expected) {
+		t.Errorf("result: %v, expected: %v", timers, expected)
+	}
+
+	if len(before) != 24 || len(after) != 24 {
+		t.Fatalf("result: %d and %d, expected: %d", len(before), len(after), 24)
+	}
+	if before[3] != 0x206 || after[3] != 0x220 {
+		t.Errorf("result: 0x%03X and 0x%03X, expected: 0x%03X and 0x%03X", before[3], after[3], 0x206, 0x220)
+	}
+
+	t.Run("when a key is polled", func(t *testing.T) {
+		events = nil
+		cpu := chip8.NewCpu(&chip8.ConfigCpu{
+			Keyboard: MockKeyBoard{Key: 0x05},
+			Memory:   chip8.NewStandardMemory(&chip8.ConfigMemory{Rom: &MockRom{}}),
+			Hooks:    recordHooks(&events),
+		})
+
+		for _, instr := range []chip8.Instruction{{0xE1, 0x9E}, {0xE1, 0xA1}, {0xF1, 0x0A}} {
+			if err := cpu.Process(instr); err != nil {
+				t.Fatalf("error not expected: %s", err.Error())
+			}
+		}
+
+		if expected := []string{"key 05", "key 05", "key 05"}; !reflect.DeepEqual(events, expected) {
+			t.Errorf("result: %v, expected: %v", events, expected)
+		}
+	})
+
+	t.Run("when hooks are removed", func(t *testing.T) {
+		events = nil
+		cpu.SetHooks(chip8.Hooks{})
+
+		if err := cpu.RunCycles(24); err != nil {
+			t.Fatalf("error not expected: %s", err.Error())
+		}
+
+		if len(events) != 0 {
+			t.Errorf("result: %v, expected: []", events)
+		}
+	})
+}
+
+func TestCpu_HooksMemoryWrite(t *testing.T) {
+	var events []string
+	memory := chip8.NewStandardMemory(&chip8.ConfigMemory{Rom: &MockRom{}})
+	cpu := chip8.NewCpu(&chip8.ConfigCpu{
+		Memory:   memory,
+		Register: chip8.Register{0x01, 0x02, 0x03},
+		I:        0x300,
+		Hooks:    recordHooks(&events),
+	})
+
+	for _, instr := range []chip8.Instruction{{0xF2, 0x55}, {0x52, 0x02}, {0x50, 0x23}} {
+		if err := cpu.Process(instr); err != nil {
+			t.Fatalf("error not expected: %s", err.Error())
+		}
+	}
+
+	expected := []string{"write 0x300 [1 2 3]", "write 0x300 [3 2 1]", "read 0x300 [3 2 1]"}
+	if !reflect.DeepEqual(events, expected) {
+		t.Errorf("result: %v, expected: %v", events, expected)
+	}
+}
+
+func TestCpu_HooksNoAllocation(t *testing.T) {
+	cpu := chip8.NewCpu(&chip8.ConfigCpu{})
+	instr := chip8.Instruction{0x61, 0x05}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		cpu.Process(instr)
+	})
+
+	if allocs != 0 {
+		t.Errorf("result: %v, expected: %v", allocs, 0)
+	}
+}
@@ -0,0 +1,109 @@
+package chip8_test
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	chip8 "github.com/MarceloMPJR/go-chip-8"
+)
+
+func TestImage(t *testing.T) {
+	disp := chip8.NewStandardDisplay(&chip8.ConfigDisplay{Output: &bytes.Buffer{}})
+	disp.Draw(1, 2, 0x80)
+	disp.SelectPlanes(0x3)
+	disp.Draw(63, 31, 0x80)
+
+	img, err := chip8.Image(disp, &chip8.ConfigImage{Scale: 2})
+	if err != nil {
+		t.Fatalf("error not expected: %s", err.Error())
+	}
+
+	if result, expected := img.Bounds(), image.Rect(0, 0, 128, 64); result != expected {
+		t.Errorf("result: %v, expected: %v", result, expected)
+	}
+
+	pixels := []struct {
+		x, y     int
+		expected uint8
+	}{{2, 4, 1}, {3, 5, 1}, {4, 4, 0}, {2, 6, 0}, {126, 62, 3}, {127, 63, 3}, {0, 0, 0}}
+	for _, pixel := range pixels {
+		if result := img.ColorIndexAt(pixel.x, pixel.y); result != pixel.expected {
+			t.Errorf("(%d, %d) result: %d, expected: %d", pixel.x, pixel.y, result, pixel.expected)
+		}
+	}
+
+	t.Run("when palette has two colors", func(t *testing.T) {
+		palette := color.Palette{color.RGBA{0x99, 0x66, 0x00, 0xFF}, color.RGBA{0xFF, 0xCC, 0x00, 0xFF}}
+		img, err := chip8.Image(disp, &chip8.ConfigImage{Palette: palette})
+		if err != nil {
+			t.Fatalf("error not expected: %s", err.Error())
+		}
+
+		if result := img.Bounds(); result != image.Rect(0, 0, 64, 32) {
+			t.Errorf("result: %v, expected: %v", result, image.Rect(0, 0, 64, 32))
+		}
+
+		if result := img.At(63, 31); result != palette[1] {
+			t.Errorf("result: %v, expected: %v", result, palette[1])
+		}
+
+		if result := img.At(0, 0); result != palette[0] {
+			t.Errorf("result: %v, expected: %v", result, palette[0])
+		}
+	})
+}
+
+// brokenFramebuffer returns less pixels than its width and height
+type brokenFramebuffer struct {
+	*chip8.StandardDisplay
+}
+
+func (bf brokenFramebuffer) Pixels(dst []byte) []byte {
+	return bf.StandardDisplay.Pixels(dst)[1:]
+}
+
+func TestImage_InvalidFramebuffer(t *testing.T) {
+	framebuffer := brokenFramebuffer{chip8.NewStandardDisplay(&chip8.ConfigDisplay{Output: &bytes.Buffer{}})}
+
+	if _, err := chip8.Image(framebuffer, &chip8.ConfigImage{}); !errors.Is(err, chip8.ErrInvalidFramebuffer) {
+		t.Errorf("result: %v, expected: %v", err, chip8.ErrInvalidFramebuffer)
+	}
+
+	if err := chip8.Screenshot(&bytes.Buffer{}, framebuffer, &chip8.ConfigImage{}); !errors.Is(err, chip8.ErrInvalidFramebuffer) {
+		t.Errorf("result: %v, expected: %v", err, chip8.ErrInvalidFramebuffer)
+	}
+}
+
+func TestScreenshot(t *testing.T) {
+	disp := chip8.NewStandardDisplay(&chip8.ConfigDisplay{Output: &bytes.Buffer{}})
+	disp.SetHighResolution(true)
+	disp.Draw(10, 20, 0x80)
+
+	buf := &bytes.Buffer{}
+	if err := chip8.Screenshot(buf, disp, &chip8.ConfigImage{Scale: 3}); err != nil {
+		t.Fatalf("error not expected: %s", err.Error())
+	}
+
+	img, err := png.Decode(buf)
+	if err != nil {
+		t.Fatalf("error not expected: %s", err.Error())
+	}
+
+	if result, expected := img.Bounds(), image.Rect(0, 0, 384, 192); result != expected {
+		t.Errorf("result: %v, expected: %v", result, expected)
+	}
+
+	for _, point := range []image.Point{{30, 60}, {32, 62}} {
+		if r, g, b, _ := img.At(point.X, point.Y).RGBA(); r != 0xFFFF || g != 0xFFFF || b != 0xFFFF {
+			t.Errorf("%v result: (%d, %d, %d), expected: white", point, r, g, b)
+		}
+	}
+
+	if r, _, _, _ := img.At(33, 60).RGBA(); r != 0 {
+		t.Errorf("result: %d, expected: black", r)
+	}
+}
@@ -158,3 +158,54 @@ func checkResult(t *testing.T, result uint16, err error, test instrTestCase) {
 		t.Errorf("result: 0x%X, expected: 0x%X", result, test.expected)
 	}
 }
+
+func TestInstruction_String(t *testing.T) {
+	tests := []struct {
+		instr    chip8.Instruction
+		expected string
+	}{
+		{chip8.Instruction{0x00, 0xE0}, "CLS"},
+		{chip8.Instruction{0x00, 0xEE}, "RET"},
+		{chip8.Instruction{0x00, 0xC4}, "SCD 4"},
+		{chip8.Instruction{0x00, 0xD4}, "SCU 4"},
+		{chip8.Instruction{0x00, 0xFF}, "HIGH"},
+		{chip8.Instruction{0x01, 0x23}, "SYS 0x123"},
+		{chip8.Instruction{0x12, 0x0E}, "JP 0x20E"},
+		{chip8.Instruction{0x22, 0x20}, "CALL 0x220"},
+		{chip8.Instruction{0x31, 0x00}, "SE V1, 0x00"},
+		{chip8.Instruction{0x4A, 0xFF}, "SNE VA, 0xFF"},
+		{chip8.Instruction{0x51, 0x20}, "SE V1, V2"},
+		{chip8.Instruction{0x51, 0x22}, "SAVE V1, V2"},
+		{chip8.Instruction{0x51, 0x23}, "LOAD V1, V2"},
+		{chip8.Instruction{0x63, 0x1F}, "LD V3, 0x1F"},
+		{chip8.Instruction{0x75, 0x01}, "ADD V5, 0x01"},
+		{chip8.Instruction{0x8A, 0xB4}, "ADD VA, VB"},
+		{chip8.Instruction{0x8A, 0xB7}, "SUBN VA, VB"},
+		{chip8.Instruction{0x8A, 0xBE}, "SHL VA, VB"},
+		{chip8.Instruction{0x91, 0x20}, "SNE V1, V2"},
+		{chip8.Instruction{0xA3, 0x00}, "LD I, 0x300"},
+		{chip8.Instruction{0xB3, 0x00}, "JP V0, 0x300"},
+		{chip8.Instruction{0xC2, 0x0F}, "RND V2, 0x0F"},
+		{chip8.Instruction{0xD0, 0x15}, "DRW V0, V1, 5"},
+		{chip8.Instruction{0xE1, 0x9E}, "SKP V1"},
+		{chip8.Instruction{0xE1, 0xA1}, "SKNP V1"},
+		{chip8.Instruction{0xF0, 0x00}, "LD I, LONG"},
+		{chip8.Instruction{0xF2, 0x01}, "PLANE 2"},
+		{chip8.Instruction{0xF0, 0x02}, "AUDIO"},
+		{chip8.Instruction{0xF1, 0x07}, "LD V1, DT"},
+		{chip8.Instruction{0xF1, 0x0A}, "LD V1, K"},
+		{chip8.Instruction{0xF5, 0x33}, "LD B, V5"},
+		{chip8.Instruction{0xF2, 0x55}, "LD [I], V2"},
+		{chip8.Instruction{0xF2, 0x65}, "LD V2, [I]"},
+		{chip8.Instruction{0xF2, 0x85}, "LD V2, R"},
+		{chip8.Instruction{0x5A, 0xB1}, "DW 0x5AB1"},
+		{chip8.Instruction{0xFF, 0xFF}, "DW 0xFFFF"},
+		{chip8.Instruction{0xFF}, "invalid instruction"},
+	}
+
+	for _, test := range tests {
+		if result := test.instr.String(); result != test.expected {
+			t.Errorf("result: %s, expected: %s", result, test.expected)
+		}
+	}
+}
@@ -0,0 +1,337 @@
+package chip8_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	chip8 "github.com/MarceloMPJR/go-chip-8"
+)
+
+func newMachine(t *testing.T, options ...chip8.MachineOption) *chip8.Machine {
+	t.Helper()
+
+	rom, err := os.ReadFile(filepath.Join("testdata", "counter.ch8"))
+	if err != nil {
+		t.Fatalf("error not expected: %s", err.Error())
+	}
+
+	options = append([]chip8.MachineOption{chip8.WithRandom(chip8.NewSequenceRandom(0x07))}, options...)
+	machine := chip8.NewMachine(options...)
+	if err := machine.Load(bytes.NewReader(rom)); err != nil {
+		t.Fatalf("error not expected: %s", err.Error())
+	}
+
+	return machine
+}
+
+// waitFrames waits until the emulated time of machine advances frames
+func waitFrames(t *testing.T, machine *chip8.Machine, frames int) {
+	t.Helper()
+
+	elapsed := machine.Cpu().Elapsed() + time.Duration(frames)*time.Second/60
+	deadline := time.Now().Add(5 * time.Second)
+	for machine.Cpu().Elapsed() < elapsed {
+		if time.Now().After(deadline) {
+			t.Fatalf("machine did not run %d frames", frames)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestMachine_Run(t *testing.T) {
+	machine := newMachine(t, chip8.WithClock(chip8.NewVirtualClock()))
+
+	done := make(chan error)
+	go func() {
+		done <- machine.Run(context.Background())
+	}()
+
+	waitFrames(t, machine, 10)
+
+	machine.Pause()
+	if !machine.Paused() {
+		t.Fatalf("expected machine paused, but it is running")
+	}
+
+	// The frame in progress when paused may finish
+	time.Sleep(10 * time.Millisecond)
+	paused := machine.Cpu().State()
+	time.Sleep(10 * time.Millisecond)
+	if result := machine.Cpu().State(); result != paused {
+		t.Errorf("result: %+v, expected: %+v", result, paused)
+	}
+
+	machine.Resume()
+	waitFrames(t, machine, 10)
+
+	machine.Stop()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("error not expected: %s", err.Error())
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Run did not return after Stop")
+	}
+
+	t.Run("when it is stopped while paused", func(t *testing.T) {
+		machine.Pause()
+		go func() {
+			done <- machine.Run(context.Background())
+		}()
+
+		time.Sleep(10 * time.Millisecond)
+		machine.Stop()
+
+		if err := <-done; err != nil {
+			t.Errorf("error not expected: %s", err.Error())
+		}
+	})
+
+	t.Run("when context is cancelled", func(t *testing.T) {
+		machine.Resume()
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			done <- machine.Run(ctx)
+		}()
+
+		waitFrames(t, machine, 1)
+		cancel()
+
+		if err := <-done; !errors.Is(err, context.Canceled) {
+			t.Errorf("result: %v, expected: %v", err, context.Canceled)
+		}
+	})
+}
+
+func TestMachine_RunTwice(t *testing.T) {
+	machine := newMachine(t, chip8.WithClock(chip8.NewVirtualClock()))
+
+	done := make(chan error)
+	go func() {
+		done <- machine.Run(context.Background())
+	}()
+
+	waitFrames(t, machine, 1)
+
+	if err := machine.Run(context.Background()); !errors.Is(err, chip8.ErrRunning) {
+		t.Errorf("result: %v, expected: %v", err, chip8.ErrRunning)
+	}
+
+	machine.Stop()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("error not expected: %s", err.Error())
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Run did not return after Stop")
+	}
+}
+
+func TestMachine_StopBeforeRun(t *testing.T) {
+	machine := newMachine(t, chip8.WithClock(chip8.NewVirtualClock()))
+
+	// The Stop is kept until Run starts
+	done := make(chan error)
+	machine.Stop()
+	go func() {
+		done <- machine.Run(context.Background())
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("error not expected: %s", err.Error())
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Run did not return after Stop")
+	}
+
+	if result := machine.Cpu().Elapsed(); result != 0 {
+		t.Errorf("result: %v, expected: %v", result, time.Duration(0))
+	}
+
+	t.Run("when it runs again", func(t *testing.T) {
+		go func() {
+			done <- machine.Run(context.Background())
+		}()
+
+		waitFrames(t, machine, 1)
+		machine.Stop()
+
+		if err := <-done; err != nil {
+			t.Errorf("error not expected: %s", err.Error())
+		}
+	})
+}
+
+func TestMachine_StopAfterRun(t *testing.T) {
+	machine := chip8.NewMachine()
+	if err := machine.Load(bytes.NewReader([]byte{0x00, 0xFD})); err != nil {
+		t.Fatalf("error not expected: %s", err.Error())
+	}
+
+	if err := machine.Run(context.Background()); err != nil {
+		t.Fatalf("error not expected: %s", err.Error())
+	}
+
+	// The frontend stops the machine after Run returned, then loads another ROM
+	machine.Stop()
+	if err := machine.Load(bytes.NewReader([]byte{0x60, 0x42, 0x00, 0xFD})); err != nil {
+		t.Fatalf("error not expected: %s", err.Error())
+	}
+
+	if err := machine.Run(context.Background()); err != nil {
+		t.Fatalf("error not expected: %s", err.Error())
+	}
+
+	if result := machine.Cpu().State().Register[0x0]; result != 0x42 {
+		t.Errorf("result: 0x%02X, expected: 0x%02X", result, 0x42)
+	}
+
+	t.Run("when machine is reset", func(t *testing.T) {
+		machine.Stop()
+		machine.Reset()
+
+		if err := machine.Run(context.Background()); err != nil {
+			t.Fatalf("error not expected: %s", err.Error())
+		}
+
+		if result := machine.Cpu().State().PC; result != 0x202 {
+			t.Errorf("result: 0x%03X, expected: 0x%03X", result, 0x202)
+		}
+	})
+}
+
+func TestMachine_Halt(t *testing.T) {
+	machine := chip8.NewMachine()
+	if err := machine.Load(bytes.NewReader([]byte{0x00, 0xFD})); err != nil {
+		t.Fatalf("error not expected: %s", err.Error())
+	}
+
+	if err := machine.Run(context.Background()); err != nil {
+		t.Errorf("error not expected: %s", err.Error())
+	}
+
+	t.Run("when ROM is invalid", func(t *testing.T) {
+		if err := machine.Load(bytes.NewReader([]byte{0xFF, 0xFF})); err != nil {
+			t.Fatalf("error not expected: %s", err.Error())
+		}
+
+		var unknown *chip8.ErrUnknownOpcode
+		if err := machine.Run(context.Background()); !errors.As(err, &unknown) {
+			t.Errorf("result: %v, expected: %T", err, unknown)
+		}
+	})
+}
+
+func TestMachine_Reset(t *testing.T) {
+	expected := newMachine(t)
+	if err := expected.Cpu().RunCycles(100); err != nil {
+		t.Fatalf("error not expected: %s", err.Error())
+	}
+
+	machine := newMachine(t)
+	if err := machine.Cpu().RunCycles(250); err != nil {
+		t.Fatalf("error not expected: %s", err.Error())
+	}
+
+	machine.Reset()
+	if err := machine.Cpu().RunCycles(100); err != nil {
+		t.Fatalf("error not expected: %s", err.Error())
+	}
+
+	if result := machine.Cpu().State(); result != expected.Cpu().State() {
+		t.Errorf("result: %+v, expected: %+v", result, expected.Cpu().State())
+	}
+}
+
+func TestMachine_Options(t *testing.T) {
+	t.Run("platform XO-CHIP", func(t *testing.T) {
+		machine := chip8.NewMachine(chip8.WithPlatform(chip8.PlatformXOCHIP))
+
+		if err := machine.Cpu().SetI(0xFFFF); err != nil {
+			t.Errorf("error not expected: %s", err.Error())
+		}
+
+		if result := machine.Memory().(*chip8.StandardMemory).Size(); result != chip8.XOChipMemorySize {
+			t.Errorf("result: %d, expected: %d", result, chip8.XOChipMemorySize)
+		}
+	})
+
+	t.Run("platform VIP", func(t *testing.T) {
+		machine := chip8.NewMachine(chip8.WithPlatform(chip8.PlatformVIP))
+		rom := []byte{0x60, 0x01, 0x61, 0x03, 0x80, 0x16}
+		if err := machine.Load(bytes.NewReader(rom)); err != nil {
+			t.Fatalf("error not expected: %s", err.Error())
+		}
+
+		if err := machine.Cpu().RunCycles(3); err != nil {
+			t.Fatalf("error not expected: %s", err.Error())
+		}
+
+		// 8XY6 shifts VY on VIP
+		if result := machine.Cpu().State().Register[0x0]; result != 0x01 {
+			t.Errorf("result: %d, expected: %d", result, 0x01)
+		}
+	})
+
+	t.Run("instructions of platform", func(t *testing.T) {
+		tests := []struct {
+			platform chip8.Platform
+			opcode   uint16
+			known    bool
+		}{
+			{chip8.PlatformVIP, 0x00FF, false},
+			{chip8.PlatformVIP, 0xF175, false},
+			{chip8.PlatformVIP, 0xF101, false},
+			{chip8.PlatformVIP, 0x5012, false},
+			{chip8.PlatformSCHIP, 0x00FF, true},
+			{chip8.PlatformSCHIP, 0xF175, true},
+			{chip8.PlatformSCHIP, 0x00D1, false},
+			{chip8.PlatformSCHIP, 0xF101, false},
+			{chip8.PlatformSCHIP, 0xF03A, false},
+			{chip8.PlatformXOCHIP, 0x00D1, true},
+			{chip8.PlatformXOCHIP, 0xF101, true},
+			{chip8.PlatformModern, 0xF101, true},
+		}
+
+		for _, test := range tests {
+			machine := chip8.NewMachine(chip8.WithPlatform(test.platform))
+			if err := machine.Load(bytes.NewReader([]byte{byte(test.opcode >> 8), byte(test.opcode)})); err != nil {
+				t.Fatalf("error not expected: %s", err.Error())
+			}
+
+			err := machine.Cpu().Step()
+			var unknown *chip8.ErrUnknownOpcode
+			if known := !errors.As(err, &unknown); known != test.known {
+				t.Errorf("platform %d opcode %04X result: %v, expected known: %v", test.platform, test.opcode, err, test.known)
+			}
+		}
+	})
+
+	t.Run("speed", func(t *testing.T) {
+		machine := chip8.NewMachine(chip8.WithSpeed(600))
+		if err := machine.Load(bytes.NewReader(bytes.Repeat([]byte{0x60, 0x00}, 20))); err != nil {
+			t.Fatalf("error not expected: %s", err.Error())
+		}
+
+		if err := machine.Cpu().RunFrame(); err != nil {
+			t.Fatalf("error not expected: %s", err.Error())
+		}
+
+		if result := machine.Cpu().Elapsed(); result != time.Second/60 {
+			t.Errorf("result: %v, expected: %v", result, time.Second/60)
+		}
+
+		if result := machine.Cpu().State().PC; result != 0x200+2*10 {
+			t.Errorf("result: 0x%03X, expected: 0x%03X", result, 0x200+2*10)
+		}
+	})
+}
@@ -1,10 +1,25 @@
 package chip8_test
 
-import chip8 "github.com/MarceloMPJR/go-chip-8"
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	chip8 "github.com/MarceloMPJR/go-chip-8"
+)
 
 type MockDisplay struct {
 	clearCount int
 	drawCount  int
+	draws      []MockDraw
+}
+
+type MockDraw struct {
+	xDisplay byte
+	yDisplay byte
+	sprite   byte
 }
 
 func (md *MockDisplay) Clear() {
@@ -13,6 +28,7 @@ func (md *MockDisplay) Clear() {
 
 func (md *MockDisplay) Draw(xDisplay, yDisplay, sprite byte) bool {
 	md.drawCount++
+	md.draws = append(md.draws, MockDraw{xDisplay: xDisplay, yDisplay: yDisplay, sprite: sprite})
 
 	return false
 }
@@ -20,6 +36,60 @@ func (md *MockDisplay) Draw(xDisplay, yDisplay, sprite byte) bool {
 func (md *MockDisplay) Flush() {
 }
 
+type MockHiResDisplay struct {
+	MockDisplay
+	hiRes            bool
+	scrollDown       byte
+	scrollUp         byte
+	scrollRightCount int
+	scrollLeftCount  int
+}
+
+func (md *MockHiResDisplay) SetHighResolution(enabled bool) {
+	md.hiRes = enabled
+}
+
+func (md *MockHiResDisplay) HighResolution() bool {
+	return md.hiRes
+}
+
+func (md *MockHiResDisplay) ScrollDown(n byte) {
+	md.scrollDown += n
+}
+
+func (md *MockHiResDisplay) ScrollUp(n byte) {
+	md.scrollUp += n
+}
+
+func (md *MockHiResDisplay) ScrollRight() {
+	md.scrollRightCount++
+}
+
+func (md *MockHiResDisplay) ScrollLeft() {
+	md.scrollLeftCount++
+}
+
+type MockPlaneDisplay struct {
+	MockHiResDisplay
+	planes     byte
+	planeDraws []MockPlaneDraw
+}
+
+type MockPlaneDraw struct {
+	plane byte
+	MockDraw
+}
+
+func (md *MockPlaneDisplay) SelectPlanes(mask byte) {
+	md.planes = mask
+}
+
+func (md *MockPlaneDisplay) DrawPlane(plane, xDisplay, yDisplay, sprite byte) bool {
+	md.planeDraws = append(md.planeDraws, MockPlaneDraw{plane: plane, MockDraw: MockDraw{xDisplay: xDisplay, yDisplay: yDisplay, sprite: sprite}})
+
+	return false
+}
+
 type MockKeyBoard struct {
 	Key chip8.Key
 }
@@ -28,11 +98,34 @@ func (mk MockKeyBoard) KeyDown() chip8.Key {
 	return mk.Key
 }
 
+// MockStateKeyBoard is a keyboard whose key down is saved by SaveState
+type MockStateKeyBoard struct {
+	Key chip8.Key
+}
+
+func (mk *MockStateKeyBoard) KeyDown() chip8.Key {
+	return mk.Key
+}
+
+func (mk *MockStateKeyBoard) MarshalBinary() ([]byte, error) {
+	return []byte{byte(mk.Key)}, nil
+}
+
+func (mk *MockStateKeyBoard) UnmarshalBinary(data []byte) error {
+	if len(data) != 1 {
+		return fmt.Errorf("keyboard state of %d bytes, expected 1", len(data))
+	}
+
+	mk.Key = chip8.Key(data[0])
+	return nil
+}
+
 type MockMemory struct {
 	saveCount     int
 	saveBCDCount  int
 	loadCount     int
 	loadCharCount int
+	sprite        byte
 }
 
 func (mm *MockMemory) Save(register []byte, i uint16) {
@@ -56,8 +149,39 @@ func (mm *MockMemory) LoadChar(vx byte) uint16 {
 	return uint16(vx) + 0x2
 }
 
+func (mm *MockMemory) LoadBigChar(vx byte) uint16 {
+	return uint16(vx) + 0x50
+}
+
 func (mm *MockMemory) LoadSprite(i uint16) byte {
-	return 0x00
+	return mm.sprite
+}
+
+type MockSound struct {
+	beepCount int
+}
+
+func (ms *MockSound) Beep() {
+	ms.beepCount++
+}
+
+type MockPatternSound struct {
+	MockSound
+	pattern [16]byte
+	pitch   byte
+	playing []bool
+}
+
+func (ms *MockPatternSound) SetPattern(pattern [16]byte) {
+	ms.pattern = pattern
+}
+
+func (ms *MockPatternSound) SetPitch(pitch byte) {
+	ms.pitch = pitch
+}
+
+func (ms *MockPatternSound) SetPlaying(playing bool) {
+	ms.playing = append(ms.playing, playing)
 }
 
 type MockRom struct{}
@@ -65,3 +189,37 @@ type MockRom struct{}
 func (mr *MockRom) Read(p []byte) (int, error) {
 	return 0, nil
 }
+
+// syncWriter is an io.Writer safe to be read while other goroutine writes on it
+type syncWriter struct {
+	mu  sync.Mutex
+	len int
+}
+
+func (sw *syncWriter) Write(p []byte) (int, error) {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	sw.len += len(p)
+	return len(p), nil
+}
+
+func (sw *syncWriter) Len() int {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	return sw.len
+}
+
+// loadRom returns a StandardMemory with the ROM of testdata loaded
+func loadRom(t *testing.T, name string) *chip8.StandardMemory {
+	t.Helper()
+
+	f, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("error not expected: %s", err.Error())
+	}
+	defer f.Close()
+
+	return chip8.NewStandardMemory(&chip8.ConfigMemory{Rom: f})
+}
@@ -0,0 +1,233 @@
+package chip8_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	chip8 "github.com/MarceloMPJR/go-chip-8"
+	"github.com/MarceloMPJR/go-chip-8/disasm"
+	"github.com/MarceloMPJR/go-chip-8/octo"
+)
+
+func compile(t *testing.T, src string) *octo.Program {
+	t.Helper()
+
+	program, err := octo.NewCompiler(&octo.ConfigCompiler{}).Compile("main.8o", strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("error not expected: %s", err.Error())
+	}
+
+	return program
+}
+
+func TestCompiler_RoundTrip(t *testing.T) {
+	t.Run("when the source is the disassembly of a ROM", func(t *testing.T) {
+		src, err := os.ReadFile(filepath.Join("testdata", "counter.8o"))
+		if err != nil {
+			t.Fatalf("error not expected: %s", err.Error())
+		}
+
+		rom, err := os.ReadFile(filepath.Join("testdata", "counter.ch8"))
+		if err != nil {
+			t.Fatalf("error not expected: %s", err.Error())
+		}
+
+		if result := compile(t, string(src)).Rom; !bytes.Equal(result, rom) {
+			t.Errorf("result: % X, expected: % X", result, rom)
+		}
+	})
+
+	t.Run("when the source is the disassembly of all instructions", func(t *testing.T) {
+		dis := disasm.NewDisassembler(&disasm.ConfigDisassembler{Syntax: disasm.Octo})
+
+		// Each type of instruction is disassembled apart to fit on memory
+		for instrType := 0x0; instrType <= 0xF; instrType++ {
+			rom := []byte{}
+			for op := instrType << 12; op < (instrType+1)<<12; op++ {
+				rom = append(rom, byte(op>>8), byte(op))
+			}
+
+			src := &bytes.Buffer{}
+			dis.Write(src, rom)
+
+			result := compile(t, src.String()).Rom
+			for idx := 0; idx < len(rom); idx += 2 {
+				if !bytes.Equal(result[idx:idx+2], rom[idx:idx+2]) {
+					t.Fatalf("result: % X, expected: % X", result[idx:idx+2], rom[idx:idx+2])
+				}
+			}
+		}
+	})
+}
+
+func TestCompiler_Compile(t *testing.T) {
+	src := `
+: main
+	:alias counter v3
+	:const LIMIT 5
+	:calc DOUBLE { LIMIT * 2 + 1 } # right to left, it is 5 * 3
+	counter := 0
+	loop
+		counter += 1
+		while counter != LIMIT
+		if counter == 2 then v4 := DOUBLE
+	again
+	if v4 > 3 begin
+		i := sprite
+	else
+		i := long sprite
+	end
+	:unpack 0xA sprite
+	:breakpoint stop
+	exit
+: sprite
+	0xFF -1
+	:byte { LIMIT + 1 }
+	:next patched
+	v0 := 0
+	:org 0x230
+	:macro twice X { X X }
+	twice clear
+`
+	program := compile(t, src)
+
+	expected := []byte{
+		0x63, 0x00, // counter := 0
+		0x73, 0x01, // loop: counter += 1
+		0x43, 0x05, 0x12, 0x0E, // while counter != LIMIT
+		0x43, 0x02, 0x64, 0x0F, // if counter == 2 then v4 := DOUBLE
+		0x12, 0x02, // again
+		0x6F, 0x03, 0x8F, 0x45, 0x3F, 0x00, 0x12, 0x1A, // if v4 > 3 begin
+		0xA2, 0x24, // i := sprite
+		0x12, 0x1E, // else
+		0xF0, 0x00, 0x02, 0x24, // i := long sprite
+		0x60, 0xA2, 0x61, 0x24, // :unpack 0xA sprite
+		0x00, 0xFD, // exit
+		0xFF, 0xFF, 0x06, // sprite
+		0x60, 0x00, // v0 := 0
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, // :org 0x230
+		0x00, 0xE0, 0x00, 0xE0, // twice clear
+	}
+
+	if !bytes.Equal(program.Rom, expected) {
+		t.Errorf("result: % X, expected: % X", program.Rom, expected)
+	}
+
+	symbols := map[string]uint16{"main": 0x200, "sprite": 0x224, "patched": 0x228}
+	for name, addr := range symbols {
+		if result, ok := program.Symbols[name]; !ok || result != addr {
+			t.Errorf("symbol %s result: 0x%03X, expected: 0x%03X", name, result, addr)
+		}
+	}
+
+	if result := program.Breakpoints["stop"]; result != 0x222 {
+		t.Errorf("result: 0x%03X, expected: 0x%03X", result, 0x222)
+	}
+
+	t.Run("when main is not the first label", func(t *testing.T) {
+		program := compile(t, ": sub return\n: main sub")
+
+		if expected := []byte{0x12, 0x04, 0x00, 0xEE, 0x22, 0x02}; !bytes.Equal(program.Rom, expected) {
+			t.Errorf("result: % X, expected: % X", program.Rom, expected)
+		}
+	})
+}
+
+func TestCompiler_Run(t *testing.T) {
+	src := `
+: main
+	v0 := 5
+	v1 := 7
+	va := 0 if v0 < v1 then va := 1
+	vb := 0 if v0 > v1 then vb := 1
+	vc := 0 if v0 <= 5 then vc := 1
+	vd := 0 if v0 >= 6 then vd := 1
+	v2 := 0 if v1 >= v1 then v2 := 1
+	v3 := 0 if v1 <= 6 then v3 := 1
+	v4 := 0 if v0 > 4 then v4 := 1
+	v5 := 0 if v0 < 5 then v5 := 1
+
+	v6 := 0
+	v7 := 0
+	loop
+		v7 += 1
+		v6 += v7
+		while v7 != 5
+	again
+
+	if v6 == 15 begin
+		v8 := 1
+	else
+		v8 := 2
+	end
+	exit
+`
+	program := compile(t, src)
+
+	cpu := chip8.NewCpu(&chip8.ConfigCpu{
+		Display: &MockDisplay{},
+		Memory:  chip8.NewStandardMemory(&chip8.ConfigMemory{Rom: bytes.NewReader(program.Rom)}),
+		Sound:   &MockSound{},
+		PC:      0x200,
+	})
+
+	if err := cpu.RunCycles(1000); err != nil {
+		t.Fatalf("error not expected: %s", err.Error())
+	}
+
+	if !cpu.Halted() {
+		t.Fatalf("cpu expected to be halted")
+	}
+
+	expected := map[int]byte{0xA: 1, 0xB: 0, 0xC: 1, 0xD: 0, 0x2: 1, 0x3: 0, 0x4: 1, 0x5: 0, 0x6: 15, 0x8: 1}
+	register := cpu.State().Register
+	for x, value := range expected {
+		if register[x] != value {
+			t.Errorf("V%X result: %d, expected: %d", x, register[x], value)
+		}
+	}
+}
+
+func TestCompiler_Errors(t *testing.T) {
+	testCases := []struct {
+		src      string
+		expected string
+	}{
+		{"clear", "main.8o:1:1: undefined label main"},
+		{": main\n\tjump nowhere", "main.8o:2:7: undefined label nowhere"},
+		{": main\n\tv0 := 256", "main.8o:2:8: value 256 out of range -128..255"},
+		{": main\n\tsprite v0 v1 16", "main.8o:2:15: value 16 out of range 0..15"},
+		{": main\n\tscroll-up 16", "main.8o:2:12: value 16 out of range 0..15"},
+		{": main\n\tv0 := @", "main.8o:2:8: invalid number @"},
+		{": main\n\tagain", "main.8o:2:2: again without loop"},
+		{": main\n\tloop clear", "main.8o:2:2: loop without again"},
+		{": main\n\tif v0 == 1 begin clear", "main.8o:2:2: begin without end"},
+		{": main\n\telse", "main.8o:2:2: else without begin"},
+		{": main\n\twhile v0 == 1", "main.8o:2:2: while without loop"},
+		{": main\n\tif v0 < vf then clear", "main.8o:2:8: vf can not be compared by <"},
+		{": main\n\tif v0 foo 1 then clear", "main.8o:2:8: invalid comparison foo"},
+		{": main\n\tif v0 == 1 clear", "main.8o:2:13: then or begin expected"},
+		{": main\n: main", "main.8o:2:3: main already defined"},
+		{": main\n:alias v1 v2", "main.8o:2:8: v1 is a register"},
+		{": main\n\t:macro m { m }\n\tm", "main.8o:2:13: macro m expanded too many times"},
+		{": main\n\t:calc x { 1 / 0 }", "main.8o:2:14: invalid operands 1 / 0"},
+		{": main\n\t:calc x { 1 +", "main.8o:2:14: expression expected"},
+		{": main\n\tv0 :=", "main.8o:2:7: unexpected end of source"},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.expected, func(t *testing.T) {
+			_, err := octo.NewCompiler(&octo.ConfigCompiler{}).Compile("main.8o", strings.NewReader(tC.src))
+			if err == nil {
+				t.Fatalf("error expected: %s", tC.expected)
+			}
+
+			if err.Error() != tC.expected {
+				t.Errorf("result: %s, expected: %s", err.Error(), tC.expected)
+			}
+		})
+	}
+}
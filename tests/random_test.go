@@ -0,0 +1,21 @@
+package chip8_test
+
+import (
+	"testing"
+
+	chip8 "github.com/MarceloMPJR/go-chip-8"
+)
+
+func TestSequenceRandom_Intn(t *testing.T) {
+	random := chip8.NewSequenceRandom(0x01, 0xFF, 0x80)
+
+	expected := []int{0x01, 0xFF, 0x80, 0x01, 0x0F}
+	result := []int{random.Intn(0x100), random.Intn(0x100), random.Intn(0x100), random.Intn(0x100), random.Intn(0x10)}
+
+	for i := range expected {
+		if result[i] != expected[i] {
+			t.Errorf("result: %v, expected: %v", result, expected)
+			break
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package chip8_test
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"image/gif"
+	"io"
+	"testing"
+
+	chip8 "github.com/MarceloMPJR/go-chip-8"
+)
+
+func TestRecorder_GIF(t *testing.T) {
+	disp := chip8.NewStandardDisplay(&chip8.ConfigDisplay{Output: &bytes.Buffer{}})
+	recorder := chip8.NewRecorder(&chip8.ConfigRecorder{Framebuffer: disp})
+
+	buf := &bytes.Buffer{}
+	if err := recorder.Start(buf); err != nil {
+		t.Fatalf("error not expected: %s", err.Error())
+	}
+
+	if err := recorder.Start(buf); !errors.Is(err, chip8.ErrRecording) {
+		t.Errorf("result: %v, expected: %v", err, chip8.ErrRecording)
+	}
+
+	recorder.Flush(10)
+	disp.Draw(0, 0, 0x80)
+	recorder.Flush(10) // replaces the frame 10
+	disp.Draw(1, 0, 0x80)
+	recorder.Flush(13)
+	recorder.Flush(20) // without changes
+	disp.Draw(2, 0, 0x80)
+	recorder.Flush(70)
+	recorder.FrameEnd(100)
+
+	if err := recorder.Stop(); err != nil {
+		t.Fatalf("error not expected: %s", err.Error())
+	}
+
+	if recorder.Recording() {
+		t.Errorf("expected recorder stopped, but it is recording")
+	}
+
+	// Flushes after Stop are not recorded
+	recorder.Flush(80)
+
+	img, err := gif.DecodeAll(buf)
+	if err != nil {
+		t.Fatalf("error not expected: %s", err.Error())
+	}
+
+	// The last frame lasts until the frames processed before Stop
+	if result, expected := img.Delay, []int{5, 95, 50}; !equalInts(result, expected) {
+		t.Errorf("result: %v, expected: %v", result, expected)
+	}
+
+	// The low resolution is doubled to the size of high resolution
+	if result, expected := img.Image[0].Bounds().Dx(), 128; result != expected {
+		t.Errorf("result: %d, expected: %d", result, expected)
+	}
+
+	// Each frame has one pixel more, doubled on x
+	for i, frame := range img.Image {
+		for x := 0; x <= i+1; x++ {
+			expected := uint8(0)
+			if x <= i {
+				expected = 1
+			}
+
+			if result := frame.ColorIndexAt(2*x+1, 1); result != expected {
+				t.Errorf("frame %d pixel %d result: %d, expected: %d", i, x, result, expected)
+			}
+		}
+	}
+
+	t.Run("when there are no flushes", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		if err := recorder.Start(buf); err != nil {
+			t.Fatalf("error not expected: %s", err.Error())
+		}
+
+		if err := recorder.Stop(); err != nil {
+			t.Fatalf("error not expected: %s", err.Error())
+		}
+
+		img, err := gif.DecodeAll(buf)
+		if err != nil {
+			t.Fatalf("error not expected: %s", err.Error())
+		}
+
+		if result := len(img.Image); result != 1 {
+			t.Errorf("result: %d, expected: %d", result, 1)
+		}
+	})
+}
+
+func TestRecorder_InvalidFramebuffer(t *testing.T) {
+	framebuffer := brokenFramebuffer{chip8.NewStandardDisplay(&chip8.ConfigDisplay{Output: &bytes.Buffer{}})}
+	recorder := chip8.NewRecorder(&chip8.ConfigRecorder{Framebuffer: framebuffer})
+
+	if err := recorder.Start(&bytes.Buffer{}); err != nil {
+		t.Fatalf("error not expected: %s", err.Error())
+	}
+
+	recorder.Flush(1)
+	if err := recorder.Stop(); !errors.Is(err, chip8.ErrInvalidFramebuffer) {
+		t.Errorf("result: %v, expected: %v", err, chip8.ErrInvalidFramebuffer)
+	}
+}
+
+func TestRecorder_Y4M(t *testing.T) {
+	machine := newMachine(t)
+	disp := machine.Display().(chip8.Framebuffer)
+	recorder := chip8.NewRecorder(&chip8.ConfigRecorder{Framebuffer: disp, Format: chip8.RecordY4M})
+
+	buf := &bytes.Buffer{}
+	if err := recorder.Start(buf); err != nil {
+		t.Fatalf("error not expected: %s", err.Error())
+	}
+
+	// first is the frame of the first flush
+	first, flushes := uint64(0), 0
+	machine.Cpu().SetHooks(chip8.Hooks{
+		DisplayFlush: func(frames uint64) {
+			if flushes == 0 {
+				first = frames
+			}
+			flushes++
+			recorder.Flush(frames)
+		},
+		FrameEnd: recorder.FrameEnd,
+	})
+
+	for i := 0; i < 60; i++ {
+		if err := machine.Cpu().RunFrame(); err != nil {
+			t.Fatalf("error not expected: %s", err.Error())
+		}
+	}
+
+	if err := recorder.Stop(); err != nil {
+		t.Fatalf("error not expected: %s", err.Error())
+	}
+
+	reader := bufio.NewReader(buf)
+	header, err := reader.ReadString('\n')
+	if err != nil {
+		t.Fatalf("error not expected: %s", err.Error())
+	}
+
+	if expected := "YUV4MPEG2 W128 H64 F60:1 Ip A1:1 C444\n"; header != expected {
+		t.Errorf("result: %q, expected: %q", header, expected)
+	}
+
+	if flushes < 2 {
+		t.Fatalf("result: %d flushes, expected: more than one", flushes)
+	}
+
+	// A frame for each frame of emulated time since the first flush until Stop
+	if result, expected := countY4MFrames(t, reader), 60-first; result != expected {
+		t.Errorf("result: %d, expected: %d", result, expected)
+	}
+
+	t.Run("when display does not change after the first flush", func(t *testing.T) {
+		machine := chip8.NewMachine(chip8.WithDisplay(disp.(chip8.Display)))
+		if err := machine.Load(bytes.NewReader([]byte{0x00, 0xE0, 0x12, 0x02})); err != nil {
+			t.Fatalf("error not expected: %s", err.Error())
+		}
+		machine.Cpu().SetHooks(chip8.Hooks{DisplayFlush: recorder.Flush, FrameEnd: recorder.FrameEnd})
+
+		buf := &bytes.Buffer{}
+		if err := recorder.Start(buf); err != nil {
+			t.Fatalf("error not expected: %s", err.Error())
+		}
+
+		for i := 0; i < 600; i++ {
+			if err := machine.Cpu().RunFrame(); err != nil {
+				t.Fatalf("error not expected: %s", err.Error())
+			}
+		}
+
+		if err := recorder.Stop(); err != nil {
+			t.Fatalf("error not expected: %s", err.Error())
+		}
+
+		reader := bufio.NewReader(buf)
+		if _, err := reader.ReadString('\n'); err != nil {
+			t.Fatalf("error not expected: %s", err.Error())
+		}
+
+		if result := countY4MFrames(t, reader); result != 600 {
+			t.Errorf("result: %d, expected: %d", result, 600)
+		}
+	})
+}
+
+// countY4MFrames returns the frames of a Y4M video of 128x64 pixels, read after the header
+func countY4MFrames(t *testing.T, reader io.Reader) uint64 {
+	t.Helper()
+
+	frame := make([]byte, len("FRAME\n")+3*128*64)
+	frames := uint64(0)
+	for {
+		if _, err := io.ReadFull(reader, frame); err == io.EOF {
+			return frames
+		} else if err != nil {
+			t.Fatalf("error not expected: %s", err.Error())
+		}
+
+		if result := string(frame[:6]); result != "FRAME\n" {
+			t.Fatalf("result: %q, expected: %q", result, "FRAME\n")
+		}
+		frames++
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,125 @@
+package chip8_test
+
+import (
+	"errors"
+	"testing"
+
+	chip8 "github.com/MarceloMPJR/go-chip-8"
+)
+
+func recordFrames(t *testing.T, machine *stateMachine, rewinder *chip8.Rewinder, frames int) []string {
+	t.Helper()
+
+	snapshots := make([]string, frames)
+	for i := range snapshots {
+		snapshots[i] = machine.snapshot(t, 1)
+		if err := rewinder.Record(); err != nil {
+			t.Fatalf("error not expected: %s", err.Error())
+		}
+	}
+
+	return snapshots
+}
+
+func TestRewinder_Rewind(t *testing.T) {
+	machine := newStateMachine(t)
+	rewinder := chip8.NewRewinder(&chip8.ConfigRewinder{Cpu: machine.cpu})
+
+	snapshots := recordFrames(t, machine, rewinder, 120)
+
+	if result := rewinder.Frames(); result != 119 {
+		t.Errorf("result: %d, expected: %d", result, 119)
+	}
+
+	for i := 118; i >= 60; i-- {
+		if err := rewinder.Rewind(); err != nil {
+			t.Fatalf("error not expected: %s", err.Error())
+		}
+
+		if result := machine.snapshot(t, 0); result != snapshots[i] {
+			t.Fatalf("frame %d\nresult:\n%s\nexpected:\n%s\n", i, result, snapshots[i])
+		}
+	}
+
+	t.Run("when machine runs again after rewind", func(t *testing.T) {
+		other := newStateMachine(t)
+		expected := other.snapshot(t, 91)
+
+		if result := machine.snapshot(t, 30); result != expected {
+			t.Errorf("result:\n%s\nexpected:\n%s\n", result, expected)
+		}
+	})
+}
+
+func TestRewinder_RewindEmpty(t *testing.T) {
+	machine := newStateMachine(t)
+	rewinder := chip8.NewRewinder(&chip8.ConfigRewinder{Cpu: machine.cpu})
+
+	if err := rewinder.Rewind(); !errors.Is(err, chip8.ErrRewindEmpty) {
+		t.Errorf("result: %v, expected: %v", err, chip8.ErrRewindEmpty)
+	}
+
+	recordFrames(t, machine, rewinder, 2)
+	rewinder.Rewind()
+
+	if err := rewinder.Rewind(); !errors.Is(err, chip8.ErrRewindEmpty) {
+		t.Errorf("result: %v, expected: %v", err, chip8.ErrRewindEmpty)
+	}
+}
+
+func TestRewinder_Limits(t *testing.T) {
+	t.Run("when max frames is reached", func(t *testing.T) {
+		machine := newStateMachine(t)
+		rewinder := chip8.NewRewinder(&chip8.ConfigRewinder{Cpu: machine.cpu, MaxFrames: 30})
+
+		snapshots := recordFrames(t, machine, rewinder, 100)
+
+		if result := rewinder.Frames(); result != 30 {
+			t.Errorf("result: %d, expected: %d", result, 30)
+		}
+
+		for rewinder.Rewind() == nil {
+		}
+
+		if result := machine.snapshot(t, 0); result != snapshots[69] {
+			t.Errorf("result:\n%s\nexpected:\n%s\n", result, snapshots[69])
+		}
+	})
+
+	t.Run("when max bytes is reached", func(t *testing.T) {
+		const maxBytes = 16 << 10
+
+		machine := newStateMachine(t)
+		rewinder := chip8.NewRewinder(&chip8.ConfigRewinder{Cpu: machine.cpu, MaxBytes: maxBytes})
+
+		recordFrames(t, machine, rewinder, 600)
+
+		if result := rewinder.Size(); result > maxBytes {
+			t.Errorf("result: %d, expected at most: %d", result, maxBytes)
+		}
+
+		if rewinder.Frames() == 0 || rewinder.Frames() >= 599 {
+			t.Errorf("result: %d frames, expected some frames dropped", rewinder.Frames())
+		}
+	})
+}
+
+func TestRewinder_Size(t *testing.T) {
+	machine := newStateMachine(t)
+	rewinder := chip8.NewRewinder(&chip8.ConfigRewinder{Cpu: machine.cpu})
+
+	recordFrames(t, machine, rewinder, 1)
+	full := rewinder.Size()
+
+	recordFrames(t, machine, rewinder, 60)
+	perFrame := (rewinder.Size() - full) / rewinder.Frames()
+
+	if perFrame*10 > full {
+		t.Errorf("result: %d bytes per frame, expected much less than %d", perFrame, full)
+	}
+
+	rewinder.Reset()
+	if rewinder.Size() != 0 || rewinder.Frames() != 0 {
+		t.Errorf("result: %d bytes, %d frames, expected empty", rewinder.Size(), rewinder.Frames())
+	}
+}
@@ -0,0 +1,26 @@
+package chip8_test
+
+import (
+	"testing"
+
+	chip8 "github.com/MarceloMPJR/go-chip-8"
+)
+
+func TestPatternRate(t *testing.T) {
+	tests := []struct {
+		pitch    byte
+		expected float64
+	}{
+		{pitch: 64, expected: 4000},
+		{pitch: 112, expected: 8000},
+		{pitch: 16, expected: 2000},
+	}
+
+	for _, test := range tests {
+		result := chip8.PatternRate(test.pitch)
+
+		if result != test.expected {
+			t.Errorf("result: %v, expected: %v", result, test.expected)
+		}
+	}
+}
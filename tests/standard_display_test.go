@@ -2,7 +2,12 @@ package chip8_test
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
 	"testing"
+	"time"
 
 	chip8 "github.com/MarceloMPJR/go-chip-8"
 )
@@ -82,6 +87,132 @@ func TestStandardDisplay_Draw(t *testing.T) {
 	})
 }
 
+func TestStandardDisplay_SetHighResolution(t *testing.T) {
+	output := &bytes.Buffer{}
+	disp := chip8.NewStandardDisplay(&chip8.ConfigDisplay{Output: output})
+
+	disp.Draw(5, 5, 0xF0)
+	disp.SetHighResolution(true)
+	disp.Draw(120, 60, 0x80)
+	disp.Flush()
+
+	result := output.String()
+	expected := screenWithPixels(128, 64, [2]int{120, 60})
+
+	if !disp.HighResolution() {
+		t.Errorf("expected high resolution, but it is disabled")
+	}
+
+	if result != expected {
+		t.Errorf("result:\n%s\nexpected:\n%s\n", result, expected)
+	}
+}
+
+func TestStandardDisplay_Scroll(t *testing.T) {
+	tests := []struct {
+		describe string
+		scroll   func(disp *chip8.StandardDisplay)
+		expected string
+	}{
+		{
+			describe: "ScrollDown",
+			scroll:   func(disp *chip8.StandardDisplay) { disp.ScrollDown(3) },
+			expected: screenWithPixels(64, 32, [2]int{10, 8}, [2]int{11, 8}),
+		},
+		{
+			describe: "ScrollUp",
+			scroll:   func(disp *chip8.StandardDisplay) { disp.ScrollUp(3) },
+			expected: screenWithPixels(64, 32, [2]int{10, 2}, [2]int{11, 2}),
+		},
+		{
+			describe: "ScrollRight",
+			scroll:   func(disp *chip8.StandardDisplay) { disp.ScrollRight() },
+			expected: screenWithPixels(64, 32, [2]int{14, 5}, [2]int{15, 5}),
+		},
+		{
+			describe: "ScrollLeft",
+			scroll:   func(disp *chip8.StandardDisplay) { disp.ScrollLeft() },
+			expected: screenWithPixels(64, 32, [2]int{6, 5}, [2]int{7, 5}),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.describe, func(t *testing.T) {
+			output := &bytes.Buffer{}
+			disp := chip8.NewStandardDisplay(&chip8.ConfigDisplay{Output: output})
+
+			disp.Draw(10, 5, 0xC0)
+			test.scroll(disp)
+			disp.Flush()
+
+			result := output.String()
+			if result != test.expected {
+				t.Errorf("result:\n%s\nexpected:\n%s\n", result, test.expected)
+			}
+		})
+	}
+}
+
+func TestStandardDisplay_Planes(t *testing.T) {
+	output := &bytes.Buffer{}
+	disp := chip8.NewStandardDisplay(&chip8.ConfigDisplay{Output: output})
+
+	collision := false
+	collision = disp.DrawPlane(0x1, 0, 0, 0x80) || collision
+	collision = disp.DrawPlane(0x2, 0, 0, 0x80) || collision
+	collision = disp.DrawPlane(0x2, 2, 0, 0x80) || collision
+
+	if collision {
+		t.Errorf("unexpected colission")
+	}
+
+	// Clears only the first plane
+	disp.SelectPlanes(0x1)
+	disp.Clear()
+
+	// Draws on both planes
+	disp.SelectPlanes(0x3)
+	collision = disp.Draw(4, 0, 0x80)
+	if collision {
+		t.Errorf("unexpected colission")
+	}
+
+	collision = disp.Draw(0, 0, 0x80)
+	if !collision {
+		t.Errorf("expected colission, but do not occurs")
+	}
+
+	disp.Flush()
+
+	result := output.String()
+	expected := screenWithPixels(64, 32, [2]int{0, 0}, [2]int{2, 0}, [2]int{4, 0})
+
+	if result != expected {
+		t.Errorf("result:\n%s\nexpected:\n%s\n", result, expected)
+	}
+}
+
+func screenWithPixels(width, height int, pixels ...[2]int) string {
+	screen := make([][]string, height)
+	for y := range screen {
+		screen[y] = make([]string, width)
+		for x := range screen[y] {
+			screen[y][x] = chip8.White
+		}
+	}
+
+	for _, pixel := range pixels {
+		screen[pixel[1]][pixel[0]] = chip8.Black
+	}
+
+	str := ""
+	for y := range screen {
+		str += strings.Join(screen[y], "") + "\n"
+	}
+
+	return str
+}
+
 func initialScreen() string {
 	return `□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□
 □□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□
@@ -153,3 +284,168 @@ func drawCharFive() string {
 □□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□□
 `
 }
+
+func TestStandardDisplay_Reset(t *testing.T) {
+	output := &bytes.Buffer{}
+	disp := chip8.NewStandardDisplay(&chip8.ConfigDisplay{Output: output})
+
+	disp.SetHighResolution(true)
+	disp.SelectPlanes(0x2)
+	disp.Draw(5, 5, 0xF0)
+	disp.Reset()
+	disp.Draw(1, 1, 0x80)
+	disp.Flush()
+
+	result := output.String()
+	expected := screenWithPixels(64, 32, [2]int{1, 1})
+
+	if disp.HighResolution() {
+		t.Errorf("expected low resolution, but it is high")
+	}
+
+	if result != expected {
+		t.Errorf("result:\n%s\nexpected:\n%s\n", result, expected)
+	}
+}
+
+func TestStandardDisplay_Framebuffer(t *testing.T) {
+	var disp chip8.Framebuffer = chip8.NewStandardDisplay(&chip8.ConfigDisplay{Output: &bytes.Buffer{}})
+
+	if disp.Width() != 64 || disp.Height() != 32 {
+		t.Errorf("result: %dx%d, expected: 64x32", disp.Width(), disp.Height())
+	}
+
+	version := disp.Version()
+	disp.Draw(1, 2, 0x80)
+	disp.Flush()
+
+	if result := disp.Version(); result != version+1 {
+		t.Errorf("result: %d, expected: %d", result, version+1)
+	}
+
+	pixels := []struct {
+		x, y     int
+		expected byte
+	}{{1, 2, 0x1}, {2, 2, 0x0}, {-1, 2, 0x0}, {64, 2, 0x0}, {1, 32, 0x0}}
+	for _, pixel := range pixels {
+		if result := disp.Pixel(pixel.x, pixel.y); result != pixel.expected {
+			t.Errorf("(%d, %d) result: %d, expected: %d", pixel.x, pixel.y, result, pixel.expected)
+		}
+	}
+
+	frame := disp.Pixels(nil)
+	if len(frame) != 64*32 || frame[2*64+1] != 0x1 {
+		t.Errorf("result: %d pixels, expected: %d pixels with (1, 2) set", len(frame), 64*32)
+	}
+
+	t.Run("when display is on high resolution with planes", func(t *testing.T) {
+		disp.(*chip8.StandardDisplay).SetHighResolution(true)
+		disp.(*chip8.StandardDisplay).SelectPlanes(0x3)
+		disp.Draw(127, 63, 0x80)
+
+		if disp.Width() != 128 || disp.Height() != 64 {
+			t.Errorf("result: %dx%d, expected: 128x64", disp.Width(), disp.Height())
+		}
+
+		frame = disp.Pixels(frame)
+		if len(frame) != 128*64 || frame[len(frame)-1] != 0x3 {
+			t.Errorf("result: %d pixels, expected: %d pixels with (127, 63) set on both planes", len(frame), 128*64)
+		}
+
+		if result := disp.Version(); result != version+3 {
+			t.Errorf("result: %d, expected: %d", result, version+3)
+		}
+	})
+
+	t.Run("when Cpu draws concurrently", func(t *testing.T) {
+		display := chip8.NewStandardDisplay(&chip8.ConfigDisplay{Output: &syncWriter{}})
+		machine := newMachine(t, chip8.WithDisplay(display))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		go func() {
+			var frame []byte
+			for ctx.Err() == nil {
+				frame = display.Pixels(frame)
+				display.Version()
+			}
+		}()
+
+		if err := machine.Run(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("result: %v, expected: %v", err, context.DeadlineExceeded)
+		}
+
+		if display.Version() == 0 {
+			t.Errorf("expected pixels changed, but version is zero")
+		}
+	})
+}
+
+func TestStandardDisplay_Renderer(t *testing.T) {
+	// A square of 3x3 on the corner and a pixel on the last row of screen
+	draw := func(disp *chip8.StandardDisplay) {
+		for y := byte(0); y < 3; y++ {
+			disp.Draw(0, y, 0xE0)
+		}
+		disp.Draw(63, 31, 0x80)
+	}
+
+	testCases := []struct {
+		renderer chip8.Renderer
+		lines    int
+		first    string
+		last     string
+	}{
+		{chip8.RendererHalfBlock, 16, "███" + strings.Repeat(" ", 61), strings.Repeat(" ", 63) + "▄"},
+		{chip8.RendererBraille, 8, "⠿⠇" + strings.Repeat("⠀", 30), strings.Repeat("⠀", 31) + "⢀"},
+		{chip8.RendererASCII, 32, "###" + strings.Repeat(".", 61), strings.Repeat(".", 63) + "#"},
+	}
+
+	for _, tc := range testCases {
+		output := &bytes.Buffer{}
+		disp := chip8.NewStandardDisplay(&chip8.ConfigDisplay{Output: output, Renderer: tc.renderer})
+		draw(disp)
+		disp.Flush()
+
+		lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+		if len(lines) != tc.lines {
+			t.Fatalf("renderer %d lines result: %d, expected: %d", tc.renderer, len(lines), tc.lines)
+		}
+
+		if lines[0] != tc.first {
+			t.Errorf("renderer %d result: %q, expected: %q", tc.renderer, lines[0], tc.first)
+		}
+
+		if result := lines[len(lines)-1]; result != tc.last {
+			t.Errorf("renderer %d result: %q, expected: %q", tc.renderer, result, tc.last)
+		}
+	}
+
+	t.Run("when it is on high resolution", func(t *testing.T) {
+		output := &bytes.Buffer{}
+		disp := chip8.NewStandardDisplay(&chip8.ConfigDisplay{Output: output, Renderer: chip8.RendererBraille})
+		disp.SetHighResolution(true)
+		disp.Flush()
+
+		expected := strings.Repeat(strings.Repeat("⠀", 64)+"\n", 16)
+		if result := output.String(); result != expected {
+			t.Errorf("result:\n%s\nexpected:\n%s\n", result, expected)
+		}
+	})
+}
+
+func TestStandardDisplay_FlushNoAllocation(t *testing.T) {
+	for _, renderer := range []chip8.Renderer{chip8.RendererBlocks, chip8.RendererHalfBlock, chip8.RendererBraille, chip8.RendererASCII} {
+		disp := chip8.NewStandardDisplay(&chip8.ConfigDisplay{Output: io.Discard, Renderer: renderer})
+		disp.SetHighResolution(true)
+		disp.Draw(10, 10, 0xAA)
+
+		// The first flush grows the buffer
+		disp.Flush()
+
+		if allocs := testing.AllocsPerRun(10, disp.Flush); allocs != 0 {
+			t.Errorf("renderer %d result: %v allocations, expected: 0", renderer, allocs)
+		}
+	}
+}
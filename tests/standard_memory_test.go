@@ -115,14 +115,48 @@ func TestStandardMemory_LoadSprite(t *testing.T) {
 	}
 }
 
+func TestStandardMemory_LoadBigChar(t *testing.T) {
+	mem, _ := newMemory()
+
+	expected := uint16(0x96)
+
+	vx := byte(0x07)
+	result := mem.LoadBigChar(vx)
+
+	if result != expected {
+		t.Errorf("result: %v\nexpected: %v\n", result, expected)
+	}
+
+	sprite := mem.LoadSprite(result + 4)
+	if sprite != 0x06 {
+		t.Errorf("result: %v\nexpected: %v\n", sprite, 0x06)
+	}
+}
+
+func TestStandardMemory_Size(t *testing.T) {
+	rom := make([]byte, 0x8000)
+	rom[len(rom)-1] = 0xAB
+
+	mem := chip8.NewStandardMemory(&chip8.ConfigMemory{Rom: bytes.NewReader(rom), Size: chip8.XOChipMemorySize})
+
+	if mem.Size() != 0x10000 {
+		t.Errorf("result: %v\nexpected: %v\n", mem.Size(), 0x10000)
+	}
+
+	result := mem.LoadSprite(0x200 + 0x7FFF)
+	if result != 0xAB {
+		t.Errorf("result: %v\nexpected: %v\n", result, 0xAB)
+	}
+}
+
 func newMemory() (*chip8.StandardMemory, *bytes.Buffer) {
 	log := &bytes.Buffer{}
 	mem := chip8.NewStandardMemory(&chip8.ConfigMemory{Rom: &MockRom{}, Log: log})
 	return mem, log
 }
 
-func initialMemory() [0xFFF]byte {
-	return [0xFFF]byte{
+func initialMemory() [0x1000]byte {
+	return [0x1000]byte{
 		// 0
 		0xF0, 0x90, 0x90, 0x90, 0xF0,
 		// 1
@@ -155,9 +189,133 @@ func initialMemory() [0xFFF]byte {
 		0xF0, 0x80, 0xF0, 0x80, 0xF0,
 		// F
 		0xF0, 0x80, 0xF0, 0x80, 0x80,
+		// big 0
+		0xFF, 0xFF, 0xC3, 0xC3, 0xC3, 0xC3, 0xC3, 0xC3, 0xFF, 0xFF,
+		// big 1
+		0x18, 0x78, 0x78, 0x18, 0x18, 0x18, 0x18, 0x18, 0xFF, 0xFF,
+		// big 2
+		0xFF, 0xFF, 0x03, 0x03, 0xFF, 0xFF, 0xC0, 0xC0, 0xFF, 0xFF,
+		// big 3
+		0xFF, 0xFF, 0x03, 0x03, 0xFF, 0xFF, 0x03, 0x03, 0xFF, 0xFF,
+		// big 4
+		0xC3, 0xC3, 0xC3, 0xC3, 0xFF, 0xFF, 0x03, 0x03, 0x03, 0x03,
+		// big 5
+		0xFF, 0xFF, 0xC0, 0xC0, 0xFF, 0xFF, 0x03, 0x03, 0xFF, 0xFF,
+		// big 6
+		0xFF, 0xFF, 0xC0, 0xC0, 0xFF, 0xFF, 0xC3, 0xC3, 0xFF, 0xFF,
+		// big 7
+		0xFF, 0xFF, 0x03, 0x03, 0x06, 0x0C, 0x18, 0x18, 0x18, 0x18,
+		// big 8
+		0xFF, 0xFF, 0xC3, 0xC3, 0xFF, 0xFF, 0xC3, 0xC3, 0xFF, 0xFF,
+		// big 9
+		0xFF, 0xFF, 0xC3, 0xC3, 0xFF, 0xFF, 0x03, 0x03, 0xFF, 0xFF,
+		// big A
+		0x7E, 0xFF, 0xC3, 0xC3, 0xC3, 0xFF, 0xFF, 0xC3, 0xC3, 0xC3,
+		// big B
+		0xFC, 0xFC, 0xC3, 0xC3, 0xFC, 0xFC, 0xC3, 0xC3, 0xFC, 0xFC,
+		// big C
+		0x3C, 0xFF, 0xC3, 0xC0, 0xC0, 0xC0, 0xC0, 0xC3, 0xFF, 0x3C,
+		// big D
+		0xFC, 0xFE, 0xC3, 0xC3, 0xC3, 0xC3, 0xC3, 0xC3, 0xFE, 0xFC,
+		// big E
+		0xFF, 0xFF, 0xC0, 0xC0, 0xFF, 0xFF, 0xC0, 0xC0, 0xFF, 0xFF,
+		// big F
+		0xFF, 0xFF, 0xC0, 0xC0, 0xFF, 0xFF, 0xC0, 0xC0, 0xC0, 0xC0,
 	}
 }
 
 func memToStr(mem []byte) []byte {
 	return []byte(fmt.Sprintf("memory: %v\n", mem))
 }
+
+func TestStandardMemory_LoadROM(t *testing.T) {
+	mem := chip8.NewStandardMemory(&chip8.ConfigMemory{Rom: bytes.NewReader([]byte{0x12, 0x34, 0x56})})
+
+	mem.Save([]byte{0xFF}, 0x300)
+	if err := mem.LoadROM(bytes.NewReader([]byte{0xAB})); err != nil {
+		t.Fatalf("error not expected: %s", err.Error())
+	}
+
+	result := []byte{mem.LoadSprite(0x200), mem.LoadSprite(0x201), mem.LoadSprite(0x300), mem.LoadSprite(0x0)}
+	expected := []byte{0xAB, 0x00, 0x00, 0xF0}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("result: %v\nexpected: %v\n", result, expected)
+	}
+
+	t.Run("when memory is reset", func(t *testing.T) {
+		mem.Save([]byte{0xCD, 0xEF}, 0x200)
+		mem.Reset()
+
+		if result := mem.LoadInstruction(0x200); !reflect.DeepEqual(result, chip8.Instruction{0xAB, 0x00}) {
+			t.Errorf("result: %v\nexpected: %v\n", result, chip8.Instruction{0xAB, 0x00})
+		}
+	})
+
+	t.Run("when ROM fills the memory", func(t *testing.T) {
+		rom := make([]byte, 0xE00)
+		rom[len(rom)-1] = 0xAB
+		if err := mem.LoadROM(bytes.NewReader(rom)); err != nil {
+			t.Fatalf("error not expected: %s", err.Error())
+		}
+
+		if result := mem.LoadSprite(0xFFF); result != 0xAB {
+			t.Errorf("result: %v\nexpected: %v\n", result, 0xAB)
+		}
+	})
+
+	t.Run("when ROM does not fit", func(t *testing.T) {
+		if err := mem.LoadROM(bytes.NewReader([]byte{0xAB})); err != nil {
+			t.Fatalf("error not expected: %s", err.Error())
+		}
+
+		if err := mem.LoadROM(bytes.NewReader(make([]byte, 0xE01))); err == nil {
+			t.Fatalf("result: nil\nexpected: error\n")
+		}
+
+		if result := mem.LoadSprite(0x200); result != 0xAB {
+			t.Errorf("result: %v\nexpected: %v\n", result, 0xAB)
+		}
+	})
+}
+
+func TestStandardMemory_Err(t *testing.T) {
+	mem := chip8.NewStandardMemory(&chip8.ConfigMemory{Rom: bytes.NewReader([]byte{0xAB})})
+	if err := mem.Err(); err != nil {
+		t.Errorf("error not expected: %s", err.Error())
+	}
+
+	t.Run("when size is too small", func(t *testing.T) {
+		mem := chip8.NewStandardMemory(&chip8.ConfigMemory{Rom: bytes.NewReader([]byte{0xAB}), Size: 0x1FF})
+		if mem.Err() == nil {
+			t.Errorf("error expected, but it was nil")
+		}
+
+		// The memory has 4 KiB without the ROM
+		if result := mem.Size(); result != 0x1000 {
+			t.Errorf("result: %d, expected: %d", result, 0x1000)
+		}
+
+		if result := mem.LoadInstruction(0x200); !reflect.DeepEqual(result, chip8.Instruction{0x00, 0x00}) {
+			t.Errorf("result: %v, expected: %v", result, chip8.Instruction{0x00, 0x00})
+		}
+	})
+
+	t.Run("when ROM does not fit", func(t *testing.T) {
+		rom := make([]byte, 0xE01)
+		rom[0] = 0xAB
+
+		// It is rejected as by LoadROM
+		mem := chip8.NewStandardMemory(&chip8.ConfigMemory{Rom: bytes.NewReader(rom)})
+		if mem.Err() == nil {
+			t.Errorf("error expected, but it was nil")
+		}
+
+		if err := mem.LoadROM(bytes.NewReader(rom)); err == nil {
+			t.Errorf("error expected, but it was nil")
+		}
+
+		if result := mem.LoadInstruction(0x200); !reflect.DeepEqual(result, chip8.Instruction{0x00, 0x00}) {
+			t.Errorf("result: %v, expected: %v", result, chip8.Instruction{0x00, 0x00})
+		}
+	})
+}
@@ -0,0 +1,231 @@
+package chip8_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"testing"
+
+	chip8 "github.com/MarceloMPJR/go-chip-8"
+)
+
+type stateMachine struct {
+	cpu     *chip8.Cpu
+	output  *bytes.Buffer
+	log     *bytes.Buffer
+	display *chip8.StandardDisplay
+}
+
+func newStateMachine(t *testing.T) *stateMachine {
+	t.Helper()
+
+	m := &stateMachine{output: &bytes.Buffer{}, log: &bytes.Buffer{}}
+	m.display = chip8.NewStandardDisplay(&chip8.ConfigDisplay{Output: m.output})
+	m.cpu = chip8.NewCpu(&chip8.ConfigCpu{
+		Display:  m.display,
+		Keyboard: MockKeyBoard{Key: 0xFF},
+		Memory:   loadRom(t, "counter.ch8"),
+		Sound:    &MockSound{},
+		Random:   chip8.NewSequenceRandom(0x07),
+		Log:      m.log,
+		Quirks:   chip8.QuirksVIP,
+		PC:       0x200,
+	})
+
+	return m
+}
+
+// snapshot runs n frames and returns the registers and screen of machine
+func (m *stateMachine) snapshot(t *testing.T, frames int) string {
+	t.Helper()
+
+	for i := 0; i < frames; i++ {
+		if err := m.cpu.RunFrame(); err != nil {
+			t.Fatalf("error not expected: %s", err.Error())
+		}
+	}
+
+	m.log.Reset()
+	m.output.Reset()
+	m.cpu.Log()
+	m.display.Flush()
+
+	return m.log.String() + m.output.String()
+}
+
+func TestCpu_SaveState(t *testing.T) {
+	machine := newStateMachine(t)
+	machine.snapshot(t, 30)
+
+	state := &bytes.Buffer{}
+	if err := machine.cpu.SaveState(state); err != nil {
+		t.Fatalf("error not expected: %s", err.Error())
+	}
+	saved := state.Bytes()
+
+	expected := machine.snapshot(t, 45)
+
+	t.Run("when state is loaded on the same machine", func(t *testing.T) {
+		if err := machine.cpu.LoadState(bytes.NewReader(saved)); err != nil {
+			t.Fatalf("error not expected: %s", err.Error())
+		}
+
+		if result := machine.snapshot(t, 45); result != expected {
+			t.Errorf("result:\n%s\nexpected:\n%s\n", result, expected)
+		}
+	})
+
+	t.Run("when state is loaded on a new machine", func(t *testing.T) {
+		other := newStateMachine(t)
+		if err := other.cpu.LoadState(bytes.NewReader(saved)); err != nil {
+			t.Fatalf("error not expected: %s", err.Error())
+		}
+
+		if result := other.snapshot(t, 45); result != expected {
+			t.Errorf("result:\n%s\nexpected:\n%s\n", result, expected)
+		}
+	})
+}
+
+func TestCpu_SaveStateKeyboard(t *testing.T) {
+	keyboard := &MockStateKeyBoard{Key: 0x05}
+	cpu := chip8.NewCpu(&chip8.ConfigCpu{Keyboard: keyboard, Memory: loadRom(t, "counter.ch8")})
+
+	state := &bytes.Buffer{}
+	if err := cpu.SaveState(state); err != nil {
+		t.Fatalf("error not expected: %s", err.Error())
+	}
+
+	other := &MockStateKeyBoard{Key: 0xFF}
+	cpu = chip8.NewCpu(&chip8.ConfigCpu{Keyboard: other, Memory: loadRom(t, "counter.ch8")})
+	if err := cpu.LoadState(state); err != nil {
+		t.Fatalf("error not expected: %s", err.Error())
+	}
+
+	if other.Key != 0x05 {
+		t.Errorf("result: 0x%02X, expected: 0x%02X", other.Key, 0x05)
+	}
+
+	t.Run("when keyboard has no state", func(t *testing.T) {
+		cpu := chip8.NewCpu(&chip8.ConfigCpu{Keyboard: MockKeyBoard{Key: 0xFF}, Memory: loadRom(t, "counter.ch8")})
+
+		state := &bytes.Buffer{}
+		if err := cpu.SaveState(state); err != nil {
+			t.Fatalf("error not expected: %s", err.Error())
+		}
+
+		// The keypad is not saved, so the state is loaded by any keyboard
+		cpu = chip8.NewCpu(&chip8.ConfigCpu{Keyboard: keyboard, Memory: loadRom(t, "counter.ch8")})
+		if err := cpu.LoadState(state); err != nil {
+			t.Fatalf("error not expected: %s", err.Error())
+		}
+
+		if keyboard.Key != 0x05 {
+			t.Errorf("result: 0x%02X, expected: 0x%02X", keyboard.Key, 0x05)
+		}
+	})
+}
+
+func TestCpu_LoadStateInvalid(t *testing.T) {
+	machine := newStateMachine(t)
+
+	state := &bytes.Buffer{}
+	if err := machine.cpu.SaveState(state); err != nil {
+		t.Fatalf("error not expected: %s", err.Error())
+	}
+
+	corrupted := append([]byte{}, state.Bytes()...)
+	corrupted[len(corrupted)/2] ^= 0xFF
+
+	otherVersion := append([]byte{}, state.Bytes()...)
+	otherVersion[5] = 0x02
+	binary.BigEndian.PutUint32(otherVersion[len(otherVersion)-4:], crc32.ChecksumIEEE(otherVersion[:len(otherVersion)-4]))
+
+	tests := []struct {
+		describe string
+		state    []byte
+		expected string
+	}{
+		{describe: "when state is empty", state: []byte{}, expected: "invalid save state: not a save state"},
+		{describe: "when magic is wrong", state: []byte("NOPE0000000000"), expected: "invalid save state: not a save state"},
+		{describe: "when checksum does not match", state: corrupted, expected: "invalid save state: checksum mismatch"},
+		{describe: "when version is not supported", state: otherVersion, expected: "invalid save state: version 2 not supported"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.describe, func(t *testing.T) {
+			err := machine.cpu.LoadState(bytes.NewReader(test.state))
+
+			if !errors.Is(err, chip8.ErrInvalidState) || err.Error() != test.expected {
+				t.Errorf("result: %v, expected: %v", err, test.expected)
+			}
+		})
+	}
+}
+
+// tamperState changes the section tag of state by change, computing again the checksum
+func tamperState(t *testing.T, state []byte, tag byte, change func(section []byte)) []byte {
+	t.Helper()
+
+	state = append([]byte{}, state...)
+	for offset := 6; offset < len(state)-4; {
+		size := int(binary.BigEndian.Uint32(state[offset+1 : offset+5]))
+		if state[offset] == tag {
+			change(state[offset+5 : offset+5+size])
+			binary.BigEndian.PutUint32(state[len(state)-4:], crc32.ChecksumIEEE(state[:len(state)-4]))
+			return state
+		}
+		offset += 5 + size
+	}
+
+	t.Fatalf("section %q not found", tag)
+	return nil
+}
+
+func TestCpu_LoadStateTampered(t *testing.T) {
+	machine := newStateMachine(t)
+	machine.snapshot(t, 30)
+
+	state := &bytes.Buffer{}
+	if err := machine.cpu.SaveState(state); err != nil {
+		t.Fatalf("error not expected: %s", err.Error())
+	}
+	saved := state.Bytes()
+
+	// Offsets on section of Cpu: registers, stack, flags and pattern precede PC
+	const pc, sp, planes = 80, 84, 88
+
+	tests := []struct {
+		describe string
+		state    []byte
+	}{
+		{describe: "when SP is beyond stack", state: tamperState(t, saved, 'C', func(section []byte) { section[sp] = 200 })},
+		{describe: "when planes are unknown", state: tamperState(t, saved, 'C', func(section []byte) { section[planes] = 0x4 })},
+		{describe: "when PC is beyond memory", state: tamperState(t, saved, 'C', func(section []byte) {
+			binary.BigEndian.PutUint16(section[pc:], 0xFFFE)
+		})},
+		{describe: "when display is invalid after a valid memory", state: tamperState(t,
+			tamperState(t, saved, 'M', func(section []byte) { section[0x300] ^= 0xFF }),
+			'D', func(section []byte) { section[1] = 0x7 },
+		)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.describe, func(t *testing.T) {
+			if err := machine.cpu.LoadState(bytes.NewReader(test.state)); !errors.Is(err, chip8.ErrInvalidState) {
+				t.Errorf("result: %v, expected: %v", err, chip8.ErrInvalidState)
+			}
+
+			// Nothing of state is applied
+			result := &bytes.Buffer{}
+			if err := machine.cpu.SaveState(result); err != nil {
+				t.Fatalf("error not expected: %s", err.Error())
+			}
+
+			if !bytes.Equal(result.Bytes(), saved) {
+				t.Errorf("expected machine unchanged, but state differs")
+			}
+		})
+	}
+}
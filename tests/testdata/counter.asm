@@ -0,0 +1,34 @@
+	CLS
+	LD V5, 0x00
+
+L204:
+	CLS
+	CALL L220
+	LD V0, 0x0A
+	LD DT, V0
+	LD ST, V0
+
+L20E:
+	LD V1, DT
+	SE V1, 0x00
+	JP L20E
+	ADD V5, 0x01
+	RND V2, 0x0F
+	JP L204
+	DB 0x00, 0x00, 0x00, 0x00, 0x00, 0x00
+
+L220:
+	LD I, 0x300
+	LD B, V5
+	LD V2, [I]
+	LD V3, 0x00
+	LD V4, 0x00
+	LD F, V0
+	DRW V3, V4, 5
+	ADD V3, 0x05
+	LD F, V1
+	DRW V3, V4, 5
+	ADD V3, 0x05
+	LD F, V2
+	DRW V3, V4, 5
+	RET
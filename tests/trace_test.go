@@ -0,0 +1,222 @@
+package chip8_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	chip8 "github.com/MarceloMPJR/go-chip-8"
+	"github.com/MarceloMPJR/go-chip-8/trace"
+)
+
+// runTrace runs counter.ch8 for cycles instructions, tracing them with config
+func runTrace(t *testing.T, config *trace.ConfigTracer, random byte, cycles int) []byte {
+	t.Helper()
+
+	output := &bytes.Buffer{}
+	config.Output = output
+	tracer := trace.NewTracer(config)
+
+	cpu := chip8.NewCpu(&chip8.ConfigCpu{
+		Display:  &MockDisplay{},
+		Keyboard: MockKeyBoard{Key: 0xFF},
+		Memory:   loadRom(t, "counter.ch8"),
+		Sound:    &MockSound{},
+		Random:   chip8.NewSequenceRandom(random),
+		Tracer:   tracer,
+		PC:       0x200,
+	})
+
+	if err := cpu.RunCycles(cycles); err != nil {
+		t.Fatalf("error not expected: %s", err.Error())
+	}
+	if err := tracer.Err(); err != nil {
+		t.Fatalf("error not expected: %s", err.Error())
+	}
+
+	return output.Bytes()
+}
+
+func readTrace(t *testing.T, data []byte) []trace.Entry {
+	t.Helper()
+
+	var entries []trace.Entry
+	reader := trace.NewReader(bytes.NewReader(data))
+	for {
+		entry, err := reader.Read()
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				t.Fatalf("error not expected: %s", err.Error())
+			}
+			return entries
+		}
+		entries = append(entries, entry)
+	}
+}
+
+func TestTracer_Text(t *testing.T) {
+	result := strings.Split(string(runTrace(t, &trace.ConfigTracer{}, 0x07, 20)), "\n")
+	expected := map[int]string{
+		1:  "00000001 0x202 6500 LD V5, 0x00",
+		3:  "00000003 0x206 2220 CALL 0x220         SP=00->01",
+		4:  "00000004 0x220 A300 LD I, 0x300        I=000->300",
+		17: "00000017 0x23A 00EE RET                SP=01->00",
+		19: "00000019 0x20A F015 LD DT, V0          DT=00->0A",
+	}
+
+	if len(result) != 21 {
+		t.Fatalf("result: %d lines, expected: %d lines", len(result), 21)
+	}
+
+	for i, line := range expected {
+		if result[i] != line {
+			t.Errorf("line %d\nresult: %q\nexpected: %q", i, result[i], line)
+		}
+	}
+}
+
+func TestTracer_Formats(t *testing.T) {
+	jsonLines := readTrace(t, runTrace(t, &trace.ConfigTracer{Format: trace.JSONLines}, 0x07, 200))
+	binary := readTrace(t, runTrace(t, &trace.ConfigTracer{Format: trace.Binary}, 0x07, 200))
+
+	if len(jsonLines) != 200 {
+		t.Fatalf("result: %d, expected: %d", len(jsonLines), 200)
+	}
+
+	if !reflect.DeepEqual(jsonLines, binary) {
+		t.Errorf("result: %v, expected: %v", binary, jsonLines)
+	}
+
+	expected := trace.Entry{
+		Cycle: 3, PC: 0x206, Opcode: 0x2220, Text: "CALL 0x220",
+		Changes: []trace.Change{{Target: trace.TargetSP, Old: 0, New: 1}},
+	}
+	if !reflect.DeepEqual(binary[3], expected) {
+		t.Errorf("result: %v, expected: %v", binary[3], expected)
+	}
+
+	t.Run("when trace is text", func(t *testing.T) {
+		text := readTrace(t, runTrace(t, &trace.ConfigTracer{}, 0x07, 200))
+
+		if !reflect.DeepEqual(text, jsonLines) {
+			t.Errorf("result: %v, expected: %v", text, jsonLines)
+		}
+	})
+
+	t.Run("when format is unknown", func(t *testing.T) {
+		reader := trace.NewReader(strings.NewReader("trace\n"))
+		if _, err := reader.Read(); !errors.Is(err, trace.ErrUnknownFormat) {
+			t.Errorf("result: %v, expected: %v", err, trace.ErrUnknownFormat)
+		}
+	})
+}
+
+func TestTracer_Filter(t *testing.T) {
+	t.Run("by address", func(t *testing.T) {
+		config := &trace.ConfigTracer{Format: trace.JSONLines, From: 0x220, To: 0x23A}
+		entries := readTrace(t, runTrace(t, config, 0x07, 40))
+
+		if len(entries) != 14 {
+			t.Errorf("result: %d, expected: %d", len(entries), 14)
+		}
+		for _, entry := range entries {
+			if entry.PC < 0x220 || entry.PC > 0x23A {
+				t.Errorf("result: 0x%03X, expected: 0x220 - 0x23A", entry.PC)
+			}
+		}
+	})
+
+	t.Run("by class", func(t *testing.T) {
+		config := &trace.ConfigTracer{Format: trace.JSONLines, Classes: trace.ClassDisplay | trace.ClassTimer}
+		entries := readTrace(t, runTrace(t, config, 0x07, 20))
+
+		var result []string
+		for _, entry := range entries {
+			result = append(result, entry.Text)
+		}
+
+		expected := []string{"CLS", "CLS", "DRW V3, V4, 5", "DRW V3, V4, 5", "DRW V3, V4, 5", "LD DT, V0"}
+		if !reflect.DeepEqual(result, expected) {
+			t.Errorf("result: %v, expected: %v", result, expected)
+		}
+	})
+}
+
+func TestClassify(t *testing.T) {
+	tests := map[uint16]trace.Class{
+		0x00E0: trace.ClassDisplay, 0x00EE: trace.ClassFlow, 0x00C3: trace.ClassDisplay, 0x00D3: trace.ClassDisplay, 0x00FD: trace.ClassFlow,
+		0x1200: trace.ClassFlow, 0x3105: trace.ClassFlow, 0x5120: trace.ClassFlow, 0x5122: trace.ClassMemory,
+		0x8124: trace.ClassArithmetic, 0xC1FF: trace.ClassArithmetic, 0xA300: trace.ClassMemory,
+		0xD125: trace.ClassDisplay, 0xE19E: trace.ClassInput, 0xF10A: trace.ClassInput, 0xF000: trace.ClassMemory,
+		0xF201: trace.ClassDisplay, 0xF002: trace.ClassMemory, 0xF133: trace.ClassMemory, 0xF118: trace.ClassTimer,
+		0x0123: 0, 0xE1FF: 0, 0xF1FF: 0,
+	}
+
+	for opcode, expected := range tests {
+		if result := trace.Classify(opcode); result != expected {
+			t.Errorf("%04X result: %q, expected: %q", opcode, result, expected)
+		}
+	}
+
+	t.Run("parse", func(t *testing.T) {
+		result, err := trace.ParseClass("flow, input")
+		if err != nil {
+			t.Fatalf("error not expected: %s", err.Error())
+		}
+		if expected := trace.ClassFlow | trace.ClassInput; result != expected {
+			t.Errorf("result: %q, expected: %q", result, expected)
+		}
+
+		if _, err := trace.ParseClass("sound"); err == nil {
+			t.Errorf("result: nil, expected: error")
+		}
+	})
+}
+
+func TestDiff(t *testing.T) {
+	config := &trace.ConfigTracer{Format: trace.Binary}
+	a := runTrace(t, config, 0x07, 200)
+
+	t.Run("when traces are equal", func(t *testing.T) {
+		b := runTrace(t, &trace.ConfigTracer{Format: trace.JSONLines}, 0x07, 200)
+
+		divergence, err := trace.Diff(trace.NewReader(bytes.NewReader(a)), trace.NewReader(bytes.NewReader(b)))
+		if err != nil {
+			t.Fatalf("error not expected: %s", err.Error())
+		}
+		if divergence != nil {
+			t.Errorf("result: %v, expected: nil", divergence)
+		}
+	})
+
+	t.Run("when a random number differs", func(t *testing.T) {
+		b := runTrace(t, &trace.ConfigTracer{Format: trace.Binary}, 0x08, 200)
+
+		divergence, err := trace.Diff(trace.NewReader(bytes.NewReader(a)), trace.NewReader(bytes.NewReader(b)))
+		if err != nil {
+			t.Fatalf("error not expected: %s", err.Error())
+		}
+		if divergence == nil {
+			t.Fatalf("result: nil, expected: divergence")
+		}
+
+		if divergence.A.PC != 0x216 || divergence.B.Opcode != 0xC20F {
+			t.Errorf("result: %s and %s, expected: RND V2 at 0x216", divergence.A, divergence.B)
+		}
+	})
+
+	t.Run("when a trace is shorter", func(t *testing.T) {
+		b := runTrace(t, &trace.ConfigTracer{Format: trace.Binary}, 0x07, 150)
+
+		divergence, err := trace.Diff(trace.NewReader(bytes.NewReader(a)), trace.NewReader(bytes.NewReader(b)))
+		if err != nil {
+			t.Fatalf("error not expected: %s", err.Error())
+		}
+		if divergence == nil || divergence.Index != 150 || divergence.B != nil {
+			t.Errorf("result: %v, expected: divergence at 150 with end of b", divergence)
+		}
+	})
+}
@@ -0,0 +1,125 @@
+package trace
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Class is a set of kinds of instructions, used to filter a trace
+type Class uint
+
+const (
+	// ClassFlow are jumps, calls, returns, skips and exit: 00EE 00FD 1NNN 2NNN 3XNN 4XNN 5XY0 9XY0 BNNN
+	ClassFlow Class = 1 << iota
+	// ClassArithmetic are operations on registers: 6XNN 7XNN 8XYN CXNN
+	ClassArithmetic
+	// ClassMemory are operations on I and memory: ANNN F000 5XY2 5XY3 F002 FX1E FX29 FX30 FX33 FX55 FX65 FX75 FX85
+	ClassMemory
+	// ClassDisplay are operations on display: 00E0 00CN 00DN 00FB 00FC 00FE 00FF DXYN FN01
+	ClassDisplay
+	// ClassTimer are operations on timers and sound: FX07 FX15 FX18 FX3A
+	ClassTimer
+	// ClassInput are operations on keyboard: EX9E EXA1 FX0A
+	ClassInput
+)
+
+var classNames = []struct {
+	class Class
+	name  string
+}{
+	{ClassFlow, "flow"},
+	{ClassArithmetic, "arithmetic"},
+	{ClassMemory, "memory"},
+	{ClassDisplay, "display"},
+	{ClassTimer, "timer"},
+	{ClassInput, "input"},
+}
+
+// Classes of instructions FXNN by NN, FN01 and F000 excluded
+var classes0xFX = map[byte]Class{
+	0x02: ClassMemory, 0x07: ClassTimer, 0x0A: ClassInput, 0x15: ClassTimer, 0x18: ClassTimer,
+	0x1E: ClassMemory, 0x29: ClassMemory, 0x30: ClassMemory, 0x33: ClassMemory, 0x3A: ClassTimer,
+	0x55: ClassMemory, 0x65: ClassMemory, 0x75: ClassMemory, 0x85: ClassMemory,
+}
+
+// Classify returns the class of opcode, zero when it is unknown
+func Classify(opcode uint16) Class {
+	nn := byte(opcode)
+
+	switch opcode >> 12 {
+	case 0x0:
+		switch {
+		case opcode == 0x00EE || opcode == 0x00FD:
+			return ClassFlow
+		case opcode == 0x00E0 || opcode&0xFFF0 == 0x00C0 || opcode&0xFFF0 == 0x00D0 || opcode == 0x00FB || opcode == 0x00FC ||
+			opcode == 0x00FE || opcode == 0x00FF:
+			return ClassDisplay
+		}
+	case 0x1, 0x2, 0x3, 0x4, 0x9, 0xB:
+		return ClassFlow
+	case 0x5:
+		switch opcode & 0xF {
+		case 0x0:
+			return ClassFlow
+		case 0x2, 0x3:
+			return ClassMemory
+		}
+	case 0x6, 0x7, 0x8, 0xC:
+		return ClassArithmetic
+	case 0xA:
+		return ClassMemory
+	case 0xD:
+		return ClassDisplay
+	case 0xE:
+		if nn == 0x9E || nn == 0xA1 {
+			return ClassInput
+		}
+	case 0xF:
+		switch {
+		case opcode == 0xF000:
+			return ClassMemory
+		case nn == 0x01:
+			return ClassDisplay
+		}
+
+		return classes0xFX[nn]
+	}
+
+	return 0
+}
+
+func (c Class) String() string {
+	var names []string
+	for _, cn := range classNames {
+		if c&cn.class != 0 {
+			names = append(names, cn.name)
+		}
+	}
+
+	return strings.Join(names, ",")
+}
+
+// ParseClass parses a list of class names separated by commas, as "flow,memory"
+func ParseClass(s string) (Class, error) {
+	var class Class
+
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		found := false
+		for _, cn := range classNames {
+			if cn.name == name {
+				class |= cn.class
+				found = true
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("unknown class %q", name)
+		}
+	}
+
+	return class, nil
+}
@@ -0,0 +1,42 @@
+package trace
+
+import "io"
+
+// Divergence is the first entry that differs between two traces
+type Divergence struct {
+	// Index of the entries, counting from zero on both traces
+	Index int
+
+	// A and B are the entries that differ, nil when its trace ended before
+	A *Entry
+	B *Entry
+}
+
+// Diff reads both traces until the first divergence, nil is returned when they are equal
+// Entries are equal when they have the same PC, Opcode and Changes, Cycle and Text are ignored
+func Diff(a, b *Reader) (*Divergence, error) {
+	for index := 0; ; index++ {
+		entryA, err := a.Read()
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		endA := err == io.EOF
+
+		entryB, err := b.Read()
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		endB := err == io.EOF
+
+		switch {
+		case endA && endB:
+			return nil, nil
+		case endA:
+			return &Divergence{Index: index, B: &entryB}, nil
+		case endB:
+			return &Divergence{Index: index, A: &entryA}, nil
+		case !entryA.equal(entryB):
+			return &Divergence{Index: index, A: &entryA, B: &entryB}, nil
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package trace
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	chip8 "github.com/MarceloMPJR/go-chip-8"
+)
+
+// ErrUnknownFormat is returned when a trace is not in format Text, JSONLines or Binary
+var ErrUnknownFormat = errors.New("unknown trace format")
+
+// Reader reads the entries of a trace written in any Format
+// The format is detected from the first bytes, the entries of Text take the text of disassembler
+type Reader struct {
+	input    *bufio.Reader
+	format   Format
+	detected bool
+	last     uint64
+}
+
+// NewReader returns a pointer to Reader that reads the trace from input
+func NewReader(input io.Reader) *Reader {
+	return &Reader{input: bufio.NewReader(input)}
+}
+
+// Read returns the next entry, or io.EOF at the end of trace
+func (r *Reader) Read() (Entry, error) {
+	if !r.detected {
+		if err := r.detect(); err != nil {
+			return Entry{}, err
+		}
+	}
+
+	switch r.format {
+	case Binary:
+		return r.readBinary()
+	case Text:
+		return r.readText()
+	}
+
+	return r.readJSON()
+}
+
+func (r *Reader) detect() error {
+	header, err := r.input.Peek(len(binaryMagic) + 1)
+	if err != nil && err != io.EOF {
+		return err
+	}
+
+	switch {
+	case len(header) == 0:
+		// An empty trace has no entries in any format
+		r.format = JSONLines
+	case len(header) > len(binaryMagic) && bytes.HasPrefix(header, []byte(binaryMagic)):
+		if header[len(binaryMagic)] != binaryVersion {
+			return fmt.Errorf("unsupported trace version %d", header[len(binaryMagic)])
+		}
+
+		r.format = Binary
+		r.input.Discard(len(header))
+	case header[0] == '{':
+		r.format = JSONLines
+	case header[0] >= '0' && header[0] <= '9':
+		r.format = Text
+	default:
+		return ErrUnknownFormat
+	}
+
+	r.detected = true
+	return nil
+}
+
+func (r *Reader) readJSON() (Entry, error) {
+	var entry Entry
+
+	line, err := r.input.ReadBytes('\n')
+	if len(bytes.TrimSpace(line)) == 0 {
+		if err == nil {
+			return r.readJSON()
+		}
+		return Entry{}, err
+	}
+
+	if err := json.Unmarshal(line, &entry); err != nil {
+		return Entry{}, err
+	}
+
+	return entry, nil
+}
+
+// readText parses a line written by Entry.String: cycle, PC, opcode, disassembly and the changes
+func (r *Reader) readText() (Entry, error) {
+	line, err := r.input.ReadString('\n')
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		if err == nil {
+			return r.readText()
+		}
+		return Entry{}, err
+	}
+
+	if len(fields) < 3 {
+		return Entry{}, fmt.Errorf("invalid trace line %q", strings.TrimSpace(line))
+	}
+
+	cycle, errCycle := strconv.ParseUint(fields[0], 10, 64)
+	pc, errPC := strconv.ParseUint(strings.TrimPrefix(fields[1], "0x"), 16, 16)
+	opcode, errOpcode := strconv.ParseUint(fields[2], 16, 16)
+	if errCycle != nil || errPC != nil || errOpcode != nil {
+		return Entry{}, fmt.Errorf("invalid trace line %q", strings.TrimSpace(line))
+	}
+
+	entry := Entry{Cycle: cycle, PC: uint16(pc), Opcode: uint16(opcode)}
+	entry.Text = chip8.Instruction{byte(opcode >> 8), byte(opcode)}.String()
+
+	// The changes are the last fields, as "SP=00->01"
+	first := len(fields)
+	for first > 3 && strings.Contains(fields[first-1], "->") {
+		first--
+	}
+
+	for _, field := range fields[first:] {
+		change, err := parseChange(field)
+		if err != nil {
+			return Entry{}, err
+		}
+		entry.Changes = append(entry.Changes, change)
+	}
+
+	return entry, nil
+}
+
+// parseChange parses a change written by Entry.String, as "I=000->300"
+func parseChange(field string) (Change, error) {
+	var change Change
+
+	equal, arrow := strings.IndexByte(field, '='), strings.Index(field, "->")
+	if equal < 0 || arrow < equal {
+		return change, fmt.Errorf("invalid trace change %q", field)
+	}
+
+	if err := change.Target.UnmarshalText([]byte(field[:equal])); err != nil {
+		return change, err
+	}
+
+	old, errOld := strconv.ParseUint(field[equal+1:arrow], 16, 16)
+	value, errNew := strconv.ParseUint(field[arrow+2:], 16, 16)
+	if errOld != nil || errNew != nil {
+		return change, fmt.Errorf("invalid trace change %q", field)
+	}
+
+	change.Old, change.New = uint16(old), uint16(value)
+	return change, nil
+}
+
+func (r *Reader) readBinary() (Entry, error) {
+	var entry Entry
+	var header [5]byte
+
+	delta, err := binary.ReadUvarint(r.input)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	if _, err := io.ReadFull(r.input, header[:]); err != nil {
+		return Entry{}, unexpectedEOF(err)
+	}
+
+	entry.Cycle = r.last + delta
+	entry.PC = binary.BigEndian.Uint16(header[0:2])
+	entry.Opcode = binary.BigEndian.Uint16(header[2:4])
+	entry.Text = chip8.Instruction{header[2], header[3]}.String()
+	r.last = entry.Cycle
+
+	for i := 0; i < int(header[4]); i++ {
+		target, err := r.input.ReadByte()
+		if err != nil {
+			return Entry{}, unexpectedEOF(err)
+		}
+
+		old, err := binary.ReadUvarint(r.input)
+		if err != nil {
+			return Entry{}, unexpectedEOF(err)
+		}
+
+		value, err := binary.ReadUvarint(r.input)
+		if err != nil {
+			return Entry{}, unexpectedEOF(err)
+		}
+
+		entry.Changes = append(entry.Changes, Change{Target(target), uint16(old), uint16(value)})
+	}
+
+	return entry, nil
+}
+
+// unexpectedEOF converts io.EOF in the middle of an entry to io.ErrUnexpectedEOF
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
@@ -0,0 +1,281 @@
+package trace
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	chip8 "github.com/MarceloMPJR/go-chip-8"
+)
+
+// Format is how the entries of a trace are written
+type Format int
+
+const (
+	// Text writes a line readable by humans for each entry
+	Text Format = iota
+	// JSONLines writes a JSON object by line for each entry
+	JSONLines
+	// Binary writes a header followed by the entries packed with varints
+	Binary
+)
+
+// binaryMagic starts a trace in format Binary, followed by binaryVersion
+const binaryMagic = "CH8T"
+const binaryVersion = 1
+
+// Target is a register changed by an instruction
+type Target byte
+
+const (
+	// TargetV0 is register V0, TargetV0 + X is register VX
+	TargetV0 Target = 0x00
+	TargetVF Target = 0x0F
+	TargetI  Target = 0x10
+	TargetDT Target = 0x11
+	TargetST Target = 0x12
+	TargetSP Target = 0x13
+)
+
+var targetNames = map[Target]string{TargetI: "I", TargetDT: "DT", TargetST: "ST", TargetSP: "SP"}
+
+func (t Target) String() string {
+	if t <= TargetVF {
+		return fmt.Sprintf("V%X", byte(t))
+	}
+	if name, ok := targetNames[t]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("Target(%d)", byte(t))
+}
+
+// MarshalText encodes the target with its name, as "V3" or "DT"
+func (t Target) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText decodes a name encoded by MarshalText
+func (t *Target) UnmarshalText(text []byte) error {
+	for target := TargetV0; target <= TargetSP; target++ {
+		if target.String() == string(text) {
+			*t = target
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown target %q", text)
+}
+
+// Change is the value of a register before and after an instruction
+type Change struct {
+	Target Target `json:"target"`
+	Old    uint16 `json:"old"`
+	New    uint16 `json:"new"`
+}
+
+// Entry is an instruction traced
+type Entry struct {
+	// Cycle is the number of instructions processed before it since the trace started,
+	// counting those filtered out
+	Cycle uint64 `json:"cycle"`
+
+	// PC is the address of instruction and Opcode its first two bytes
+	PC     uint16 `json:"pc"`
+	Opcode uint16 `json:"opcode"`
+
+	// Text is the disassembly of instruction, see chip8.Instruction.String
+	Text string `json:"text"`
+
+	// Changes are the registers changed by the instruction, PC excluded
+	Changes []Change `json:"changes,omitempty"`
+}
+
+// String formats the entry as a line of format Text, without the line break
+func (e Entry) String() string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "%08d 0x%03X %04X %-18s", e.Cycle, e.PC, e.Opcode, e.Text)
+	for _, change := range e.Changes {
+		if change.Target == TargetI {
+			fmt.Fprintf(&sb, " %s=%03X->%03X", change.Target, change.Old, change.New)
+		} else {
+			fmt.Fprintf(&sb, " %s=%02X->%02X", change.Target, change.Old, change.New)
+		}
+	}
+
+	return strings.TrimRight(sb.String(), " ")
+}
+
+// equal reports if the entries executed the same instruction with the same changes, ignoring Cycle
+func (e Entry) equal(other Entry) bool {
+	if e.PC != other.PC || e.Opcode != other.Opcode || len(e.Changes) != len(other.Changes) {
+		return false
+	}
+
+	for i := range e.Changes {
+		if e.Changes[i] != other.Changes[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Tracer implements chip8.Tracer writing the instructions processed by Cpu to an output
+// A Tracer must be used by a single Cpu
+type Tracer struct {
+	output  io.Writer
+	format  Format
+	from    uint16
+	to      uint16
+	classes Class
+	cycle   uint64
+	last    uint64
+	started bool
+	err     error
+}
+
+type ConfigTracer struct {
+	Output io.Writer
+	Format Format
+
+	// Addresses of instructions traced, inclusive, when To is zero it is 0xFFFF
+	From uint16
+	To   uint16
+
+	// Classes of instructions traced, when zero all instructions are traced
+	Classes Class
+}
+
+// NewTracer is a function that receive a config as param and return a pointer to Tracer
+func NewTracer(config *ConfigTracer) *Tracer {
+	to := config.To
+	if to == 0 {
+		to = 0xFFFF
+	}
+
+	return &Tracer{
+		output:  config.Output,
+		format:  config.Format,
+		from:    config.From,
+		to:      to,
+		classes: config.Classes,
+	}
+}
+
+// Trace writes the instruction when it passes the filters
+// After the first error of output nothing more is written, see Err
+func (t *Tracer) Trace(instr chip8.Instruction, before, after chip8.State) {
+	cycle := t.cycle
+	t.cycle++
+
+	if t.err != nil || before.PC < t.from || before.PC > t.to {
+		return
+	}
+
+	var opcode uint16
+	if len(instr) >= 2 {
+		opcode = uint16(instr[0])<<8 | uint16(instr[1])
+	}
+	if t.classes != 0 && Classify(opcode)&t.classes == 0 {
+		return
+	}
+
+	entry := Entry{Cycle: cycle, PC: before.PC, Opcode: opcode, Changes: changes(before, after)}
+	if t.format != Binary {
+		entry.Text = instr.String()
+	}
+
+	t.err = t.write(entry)
+}
+
+// Err returns the first error of output
+func (t *Tracer) Err() error {
+	return t.err
+}
+
+func (t *Tracer) write(entry Entry) error {
+	switch t.format {
+	case JSONLines:
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+
+		_, err = t.output.Write(append(data, '\n'))
+		return err
+	case Binary:
+		var buf []byte
+		if !t.started {
+			buf = append([]byte(binaryMagic), binaryVersion)
+			t.started = true
+		}
+
+		buf = appendBinary(buf, entry, t.last)
+		t.last = entry.Cycle
+
+		_, err := t.output.Write(buf)
+		return err
+	}
+
+	_, err := io.WriteString(t.output, entry.String()+"\n")
+	return err
+}
+
+// appendBinary appends the entry, its cycle is encoded as the difference from the last cycle written
+func appendBinary(buf []byte, entry Entry, last uint64) []byte {
+	var word [2]byte
+
+	buf = appendUvarint(buf, entry.Cycle-last)
+	binary.BigEndian.PutUint16(word[:], entry.PC)
+	buf = append(buf, word[:]...)
+	binary.BigEndian.PutUint16(word[:], entry.Opcode)
+	buf = append(buf, word[:]...)
+
+	buf = append(buf, byte(len(entry.Changes)))
+	for _, change := range entry.Changes {
+		buf = append(buf, byte(change.Target))
+		buf = appendUvarint(buf, uint64(change.Old))
+		buf = appendUvarint(buf, uint64(change.New))
+	}
+
+	return buf
+}
+
+// changes returns the registers that differ between states, in order of Target
+func changes(before, after chip8.State) []Change {
+	var result []Change
+
+	for x := range before.Register {
+		if before.Register[x] != after.Register[x] {
+			result = append(result, Change{TargetV0 + Target(x), uint16(before.Register[x]), uint16(after.Register[x])})
+		}
+	}
+
+	values := []struct {
+		target     Target
+		old, value uint16
+	}{
+		{TargetI, before.I, after.I},
+		{TargetDT, uint16(before.DT), uint16(after.DT)},
+		{TargetST, uint16(before.ST), uint16(after.ST)},
+		{TargetSP, uint16(before.SP), uint16(after.SP)},
+	}
+	for _, v := range values {
+		if v.old != v.value {
+			result = append(result, Change{v.target, v.old, v.value})
+		}
+	}
+
+	return result
+}
+
+func appendUvarint(buf []byte, v uint64) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+
+	n := binary.PutUvarint(tmp[:], v)
+	return append(buf, tmp[:n]...)
+}